@@ -29,9 +29,43 @@ func main() {
 	}()
 
 	// parse flags and run the agent
-	url, interval, err := agent.ParseFlags()
+	cfg, err := agent.ParseFlags()
 	if err != nil {
 		log.Fatalf("Fatal to parse flags: %v", err)
 	}
-	agent.Run(ctx, url, interval)
+	if cfg.PrintConfig {
+		if err := cfg.WriteYAML(os.Stdout); err != nil {
+			log.Fatalf("Failed to print config: %v", err)
+		}
+		return
+	}
+
+	// reload configuration on SIGHUP, invalid settings keep the running configuration
+	reload := make(chan *agent.Config)
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hup:
+			}
+			log.Println("Reloading configuration...")
+			next, err := agent.LoadConfig(os.Args[1:])
+			if err != nil {
+				log.Println("Reload failed, keeping the running configuration:", err)
+				continue
+			}
+			select {
+			case reload <- next:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	if err := agent.Run(ctx, cfg, reload); err != nil {
+		log.Fatalf("Agent failed: %v", err)
+	}
 }
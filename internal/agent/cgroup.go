@@ -0,0 +1,265 @@
+package agent
+
+import (
+	"bufio"
+	"context"
+	"data_agent/internal/models"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultSysRoot = "/sys"
+
+// 64 hex digit container ID in names like docker-<id>.scope, cri-containerd-<id>.scope or /docker/<id>
+var containerIDPattern = regexp.MustCompile(`(?:^|[-:])([0-9a-f]{64})(?:\.scope)?$`)
+
+// collects resource usage of cgroups with processes from the cgroup v2 hierarchy
+type cgroupCollector struct {
+	mu      sync.Mutex
+	sysRoot string
+	once    sync.Once
+	// previous cpu usage per cgroup path for usage percent
+	prevUsage map[string]uint64
+	prevTime  time.Time
+}
+
+func (c *cgroupCollector) Name() string { return "cgroups" }
+
+func (c *cgroupCollector) Collect(ctx context.Context) (Apply, error) {
+	now := time.Now()
+	cgroups, err := ReadCgroups(ctx, c.sysRoot)
+	if err != nil {
+		return nil, err
+	}
+	if cgroups == nil {
+		root := filepath.Join(c.sysRoot, "fs", "cgroup")
+		c.once.Do(func() { log.Printf("cgroup v2 is not mounted at %s, cgroups are not reported", root) })
+		return nil, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elapsed := now.Sub(c.prevTime).Microseconds()
+	usage := make(map[string]uint64, len(cgroups))
+	for i := range cgroups {
+		cg := &cgroups[i]
+		usage[cg.Path] = cg.CPUUsageUsec
+		if prev, ok := c.prevUsage[cg.Path]; ok && elapsed > 0 && cg.CPUUsageUsec >= prev {
+			// relative to one core like process cpu usage
+			cg.CPUPercent = float64(cg.CPUUsageUsec-prev) / float64(elapsed) * 100
+		}
+	}
+	c.prevUsage = usage
+	c.prevTime = now
+
+	return func(m *models.Metric) { m.Cgroups = cgroups }, nil
+}
+
+// read every cgroup that has processes from the sys filesystem at sysRoot,
+// nil when cgroup v2 is not mounted there
+func ReadCgroups(ctx context.Context, sysRoot string) ([]models.CgroupMetric, error) {
+	root := filepath.Join(sysRoot, "fs", "cgroup")
+	// only the unified hierarchy has cgroup.controllers at its root
+	if _, err := os.Stat(filepath.Join(root, "cgroup.controllers")); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	// I/O of stacked devices and partitions is also accounted on the disk below them
+	kinds := make(map[string]string)
+	countIO := func(device string) bool {
+		kind, ok := kinds[device]
+		if !ok {
+			kind = blockDeviceKind(filepath.Join(sysRoot, "dev", "block", device))
+			kinds[device] = kind
+		}
+		return kind != models.BlockStacked && kind != models.BlockPartition
+	}
+
+	cgroups := []models.CgroupMetric{}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// cgroups are removed while walking when containers stop
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if !d.IsDir() || path == root {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if !hasProcesses(path) {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		cg, err := readCgroup(path, countIO)
+		// a cgroup removed while it was read is left out, its files read as missing or fail
+		if _, statErr := os.Stat(path); errors.Is(statErr, os.ErrNotExist) {
+			return fs.SkipDir
+		}
+		if err != nil {
+			return fmt.Errorf("read cgroup %s: %w", rel, err)
+		}
+		cg.Path = "/" + filepath.ToSlash(rel)
+		cg.ContainerID = ContainerID(cg.Path)
+		cgroups = append(cgroups, cg)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(cgroups, func(i, j int) bool { return cgroups[i].Path < cgroups[j].Path })
+	return cgroups, nil
+}
+
+// container ID encoded in the last element of a cgroup path, empty if there is none
+func ContainerID(path string) string {
+	if m := containerIDPattern.FindStringSubmatch(filepath.Base(path)); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// whether a cgroup has member processes
+func hasProcesses(dir string) bool {
+	data, err := os.ReadFile(filepath.Join(dir, "cgroup.procs"))
+	return err == nil && len(strings.TrimSpace(string(data))) > 0
+}
+
+// read cpu, memory and io statistics of one cgroup, missing controllers leave zeros
+func readCgroup(dir string, countIO func(device string) bool) (models.CgroupMetric, error) {
+	var cg models.CgroupMetric
+
+	cpuStat, err := readKeyValues(filepath.Join(dir, "cpu.stat"))
+	if err != nil {
+		return cg, err
+	}
+	cg.CPUUsageUsec = cpuStat["usage_usec"]
+	cg.CPUUserUsec = cpuStat["user_usec"]
+	cg.CPUSystemUsec = cpuStat["system_usec"]
+	cg.NrPeriods = cpuStat["nr_periods"]
+	cg.NrThrottled = cpuStat["nr_throttled"]
+	cg.ThrottledUsec = cpuStat["throttled_usec"]
+
+	if cg.MemoryCurrent, err = readCgroupValue(filepath.Join(dir, "memory.current")); err != nil {
+		return cg, err
+	}
+	// "max" means no limit and is reported as zero
+	if cg.MemoryMax, err = readCgroupValue(filepath.Join(dir, "memory.max")); err != nil {
+		return cg, err
+	}
+
+	if err := readIOStat(filepath.Join(dir, "io.stat"), countIO, &cg); err != nil {
+		return cg, err
+	}
+	return cg, nil
+}
+
+// read a flat keyed file such as cpu.stat
+func readKeyValues(path string) (map[string]uint64, error) {
+	values := make(map[string]uint64)
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return values, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		value, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of %s in %s: %w", fields[0], filepath.Base(path), err)
+		}
+		values[fields[0]] = value
+	}
+	return values, scanner.Err()
+}
+
+// read a single value file, missing files and "max" read as zero
+func readCgroupValue(path string) (uint64, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	raw := strings.TrimSpace(string(data))
+	if raw == "max" {
+		return 0, nil
+	}
+	value, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value in %s: %w", filepath.Base(path), err)
+	}
+	return value, nil
+}
+
+// sum io.stat counters over the devices selected by countIO
+func readIOStat(path string, countIO func(device string) bool, cg *models.CgroupMetric) error {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		// the first field is the major:minor device number
+		if len(fields) == 0 || !countIO(fields[0]) {
+			continue
+		}
+		for _, field := range fields[1:] {
+			key, raw, ok := strings.Cut(field, "=")
+			if !ok {
+				continue
+			}
+			value, err := strconv.ParseUint(raw, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid value of %s in io.stat: %w", key, err)
+			}
+			switch key {
+			case "rbytes":
+				cg.IOReadBytes += value
+			case "wbytes":
+				cg.IOWriteBytes += value
+			case "rios":
+				cg.IOReadOps += value
+			case "wios":
+				cg.IOWriteOps += value
+			}
+		}
+	}
+	return scanner.Err()
+}
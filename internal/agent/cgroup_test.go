@@ -0,0 +1,62 @@
+package agent_test
+
+import (
+	"context"
+	"data_agent/internal/agent"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const fixtureContainerID = "3f1c2a9b8e7d6c5b4a39281706f5e4d3c2b1a09f8e7d6c5b4a3928170f6e5d4c"
+
+// TestReadCgroups verifies that only cgroups with processes are read from the fixture hierarchy
+func TestReadCgroups(t *testing.T) {
+	cgroups, err := agent.ReadCgroups(context.Background(), "testdata/sys")
+	require.NoError(t, err)
+	require.Len(t, cgroups, 3)
+
+	assert.Equal(t, "/init.scope", cgroups[0].Path)
+	assert.Equal(t, "/system.slice/docker-"+fixtureContainerID+".scope", cgroups[1].Path)
+	assert.Equal(t, "/user.slice/user-1000.slice/session-1.scope", cgroups[2].Path)
+
+	docker := cgroups[1]
+	assert.Equal(t, fixtureContainerID, docker.ContainerID)
+	assert.Equal(t, uint64(812345), docker.CPUUsageUsec)
+	assert.Equal(t, uint64(37), docker.NrThrottled)
+	assert.Equal(t, uint64(456789), docker.ThrottledUsec)
+	assert.Equal(t, uint64(268435456), docker.MemoryCurrent)
+	assert.Equal(t, uint64(536870912), docker.MemoryMax)
+	// dm-0 stacked on sda and the partition sda1 are not counted twice, the unknown device 259:0 is counted
+	assert.Equal(t, uint64(1048576+512), docker.IOReadBytes)
+	assert.Equal(t, uint64(2097152+1024), docker.IOWriteBytes)
+	assert.Equal(t, uint64(201), docker.IOWriteOps)
+
+	session := cgroups[2]
+	assert.Empty(t, session.ContainerID)
+	assert.Zero(t, session.MemoryMax, "unlimited memory should be reported as zero")
+	assert.Zero(t, session.IOReadBytes)
+}
+
+// TestReadCgroupsWithoutUnifiedHierarchy ensures a host without cgroup v2 yields no data and no error
+func TestReadCgroupsWithoutUnifiedHierarchy(t *testing.T) {
+	cgroups, err := agent.ReadCgroups(context.Background(), t.TempDir())
+
+	assert.NoError(t, err)
+	assert.Nil(t, cgroups)
+}
+
+// TestContainerID checks container ID extraction from common runtime cgroup names
+func TestContainerID(t *testing.T) {
+	for path, want := range map[string]string{
+		"/system.slice/docker-" + fixtureContainerID + ".scope":                               fixtureContainerID,
+		"/kubepods.slice/kubepods-pod1.slice/cri-containerd-" + fixtureContainerID + ".scope": fixtureContainerID,
+		"/machine.slice/libpod-" + fixtureContainerID + ".scope":                              fixtureContainerID,
+		"/docker/" + fixtureContainerID:                                                       fixtureContainerID,
+		"/system.slice/nginx.service":                                                         "",
+		"/system.slice/docker-1234.scope":                                                     "",
+	} {
+		assert.Equal(t, want, agent.ContainerID(path), path)
+	}
+}
@@ -1,13 +1,13 @@
 package agent
 
 import (
+	"context"
 	"data_agent/internal/models"
 	"errors"
 
 	"os"
-	"time"
+	"strings"
 
-	"github.com/shirou/gopsutil/v3/cpu"
 	"github.com/shirou/gopsutil/v3/disk"
 	"github.com/shirou/gopsutil/v3/host"
 	"github.com/shirou/gopsutil/v3/mem"
@@ -43,80 +43,215 @@ func CollectHostInfo() (models.Host, error) {
 		return models.Host{}, err
 	}
 
+	// machine-id survives hostname changes and differs between clones, empty when the host has none
+	host.MachineID = ReadMachineID(machineIDFiles...)
+
 	return *host, nil
 }
 
-// collect metric information
+// files holding the systemd or D-Bus machine ID, in lookup order
+var machineIDFiles = []string{"/etc/machine-id", "/var/lib/dbus/machine-id"}
+
+// first machine ID found in files, empty when none can be read
+func ReadMachineID(files ...string) string {
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		// an empty or "uninitialized" file is left by images prepared for cloning
+		if id := strings.TrimSpace(string(data)); id != "" && id != "uninitialized" {
+			return id
+		}
+	}
+	return ""
+}
+
+// built-in collectors
+func init() {
+	RegisterCollector("uptime", func(*Config) (Collector, error) { return uptimeCollector{}, nil })
+	RegisterCollector("cpu", func(*Config) (Collector, error) { return &cpuCollector{}, nil })
+	RegisterCollector("load", func(*Config) (Collector, error) { return loadCollector{}, nil })
+	RegisterCollector("memory", func(*Config) (Collector, error) { return memoryCollector{}, nil })
+	RegisterCollector("disk", func(cfg *Config) (Collector, error) { return diskCollector{filter: cfg.diskFilter()}, nil })
+	RegisterCollector("diskio", func(cfg *Config) (Collector, error) { return &diskIOCollector{sysRoot: cfg.sysRoot()}, nil })
+	RegisterCollector("network", func(*Config) (Collector, error) { return &netCollector{}, nil })
+	RegisterCollector("processes", func(cfg *Config) (Collector, error) { return newProcessCollector(cfg) })
+	RegisterCollector("pressure", func(cfg *Config) (Collector, error) {
+		return &pressureCollector{procRoot: cfg.procRoot()}, nil
+	})
+	RegisterCollector("cgroups", func(cfg *Config) (Collector, error) {
+		return &cgroupCollector{sysRoot: cfg.sysRoot()}, nil
+	})
+	RegisterCollector("sensors", func(cfg *Config) (Collector, error) {
+		return &sensorCollector{sysRoot: cfg.sysRoot()}, nil
+	})
+	RegisterCollector("sockets", func(cfg *Config) (Collector, error) {
+		return &socketCollector{procRoot: cfg.procRoot()}, nil
+	})
+	RegisterCollector("exec", func(cfg *Config) (Collector, error) { return newExecCollector(cfg) })
+	RegisterCollector("probes", func(cfg *Config) (Collector, error) { return newProbeCollector(cfg) })
+	RegisterCollector("logs", func(cfg *Config) (Collector, error) { return newLogCollector(cfg) })
+	RegisterCollector("systemd", func(cfg *Config) (Collector, error) { return newSystemdCollector(cfg, execSystemctl{}), nil })
+}
+
+// settings each built-in collector is created from, on reload a collector is only rebuilt
+// and loses its state when they change, collectors missing here are rebuilt on any change
+var collectorInputs = map[string]func(cfg *Config) any{
+	"uptime":    func(*Config) any { return nil },
+	"cpu":       func(*Config) any { return nil },
+	"load":      func(*Config) any { return nil },
+	"memory":    func(*Config) any { return nil },
+	"disk":      func(cfg *Config) any { return cfg.diskFilter() },
+	"diskio":    func(cfg *Config) any { return cfg.sysRoot() },
+	"network":   func(*Config) any { return nil },
+	"processes": func(cfg *Config) any { return []any{cfg.TopProcesses, cfg.ProcessCmdlineMax, cfg.ProcessRedact} },
+	"pressure":  func(cfg *Config) any { return cfg.procRoot() },
+	"cgroups":   func(cfg *Config) any { return cfg.sysRoot() },
+	"sensors":   func(cfg *Config) any { return cfg.sysRoot() },
+	"sockets":   func(cfg *Config) any { return cfg.procRoot() },
+	"exec":      func(cfg *Config) any { return cfg.ExecPlugins },
+	"probes":    func(cfg *Config) any { return cfg.Probes },
+	"logs": func(cfg *Config) any {
+		return []any{cfg.LogFiles, cfg.LogPatterns, cfg.LogSampleLines, cfg.LogStateFile}
+	},
+	"systemd": func(cfg *Config) any { return cfg.SystemdUnits },
+}
+
+// collect a single sample with all built-in collectors, values computed from the difference
+// to a previous sample such as CPU usage and I/O rates are not reported
 func CollectMetricInfo() (models.Metric, error) {
-	// get uptime
-	uptime, err := host.Uptime()
+	registry, err := NewRegistryFromConfig(&Config{})
 	if err != nil {
 		return models.Metric{}, err
 	}
+	defer registry.Close()
 
-	// get CPU info
-	cpuInfo, err := cpu.Percent(time.Second, false)
-	if err != nil {
+	metric := registry.Collect(context.Background())
+	if err := metric.Validate(); err != nil {
 		return models.Metric{}, err
 	}
-	cpuUsage := 0.0
-	if len(cpuInfo) > 0 {
-		cpuUsage = cpuInfo[0]
+	return *metric, nil
+}
+
+// collects system uptime
+type uptimeCollector struct{}
+
+func (uptimeCollector) Name() string { return "uptime" }
+
+func (uptimeCollector) Collect(ctx context.Context) (Apply, error) {
+	uptime, err := host.UptimeWithContext(ctx)
+	if err != nil {
+		return nil, err
 	}
+	return func(m *models.Metric) { m.Uptime = uptime }, nil
+}
+
+// collects memory and swap usage
+type memoryCollector struct{}
+
+func (memoryCollector) Name() string { return "memory" }
 
-	// get memory info
-	memInfo, err := mem.VirtualMemory()
+func (memoryCollector) Collect(ctx context.Context) (Apply, error) {
+	memInfo, err := mem.VirtualMemoryWithContext(ctx)
 	if err != nil {
-		return models.Metric{}, err
+		return nil, err
 	}
 	if memInfo == nil {
-		return models.Metric{}, errors.New("mem.VirtualMemory returned nil")
+		return nil, errors.New("mem.VirtualMemory returned nil")
+	}
+	swapInfo, err := mem.SwapMemoryWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if swapInfo == nil {
+		return nil, errors.New("mem.SwapMemory returned nil")
 	}
+
 	memPercent := memInfo.UsedPercent
+	memory := models.MemoryMetric{
+		Total:           memInfo.Total,
+		Available:       memInfo.Available,
+		Used:            memInfo.Used,
+		Free:            memInfo.Free,
+		Cached:          memInfo.Cached,
+		Buffers:         memInfo.Buffers,
+		Shared:          memInfo.Shared,
+		Dirty:           memInfo.Dirty,
+		Slab:            memInfo.Slab,
+		SwapTotal:       swapInfo.Total,
+		SwapUsed:        swapInfo.Used,
+		SwapFree:        swapInfo.Free,
+		SwapUsedPercent: swapInfo.UsedPercent,
+		SwapIn:          swapInfo.Sin,
+		SwapOut:         swapInfo.Sout,
+	}
 
-	diskMetric, err := CollectDiskMetric()
+	// implausible swap usage is left out, memory usage is still reported
+	err = memory.Validate()
 	if err != nil {
-		return models.Metric{}, err
+		memory.SwapTotal, memory.SwapUsed, memory.SwapFree, memory.SwapUsedPercent = 0, 0, 0, 0
 	}
-	netMetric, err := CollectNetMetric()
+	return func(m *models.Metric) {
+		m.RAM = memPercent
+		m.Memory = &memory
+	}, err
+}
+
+// collects disk usage per mountpoint
+type diskCollector struct {
+	filter DiskFilter
+}
+
+func (diskCollector) Name() string { return "disk" }
+
+func (c diskCollector) Collect(context.Context) (Apply, error) {
+	diskMetric, err := collectDiskUsage(c.filter)
 	if err != nil {
-		return models.Metric{}, err
+		return nil, err
 	}
 
-	// create Metric model
-	metric := models.NewMetric(
-		uptime,
-		cpuUsage,
-		memPercent,
-		diskMetric,
-		netMetric,
-	)
-	if metric == nil {
-		return models.Metric{}, nil
+	// a filesystem with implausible usage is left out, the others are still reported
+	valid := diskMetric[:0]
+	var errs []error
+	for _, d := range diskMetric {
+		if err := d.Validate(); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		valid = append(valid, d)
 	}
-
-	return *metric, nil
+	return func(m *models.Metric) { m.Disk = valid }, errors.Join(errs...)
 }
 
-// collect disk metrics
+// collect disk metrics of real filesystems
 func CollectDiskMetric() ([]models.DiskMetric, error) {
+	return collectDiskUsage(DefaultDiskFilter())
+}
+
+// collect disk metrics of partitions selected by the filter
+func collectDiskUsage(filter DiskFilter) ([]models.DiskMetric, error) {
 	partitions, err := disk.Partitions(true)
 	if err != nil {
 		return nil, err
 	}
 
 	var metrics []models.DiskMetric
-	for _, p := range partitions {
+	for _, p := range filter.Select(partitions) {
 		usage, err := disk.Usage(p.Mountpoint)
 		if err != nil {
 			continue
 		}
 		metrics = append(metrics, models.DiskMetric{
-			Path:        p.Mountpoint,
-			Total:       usage.Total,
-			Used:        usage.Used,
-			Free:        usage.Free,
-			UsedPercent: usage.UsedPercent,
+			Path:              p.Mountpoint,
+			Total:             usage.Total,
+			Used:              usage.Used,
+			Free:              usage.Free,
+			UsedPercent:       usage.UsedPercent,
+			InodesTotal:       usage.InodesTotal,
+			InodesUsed:        usage.InodesUsed,
+			InodesFree:        usage.InodesFree,
+			InodesUsedPercent: usage.InodesUsedPercent,
 		})
 	}
 
@@ -1,11 +1,16 @@
 package agent_test
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"data_agent/internal/agent"
+	"data_agent/internal/models"
 )
 
 // TestCollectHostInfo verifies that CollectHostInfo returns valid host information without error
@@ -21,6 +26,8 @@ func TestCollectMetricInfo(t *testing.T) {
 	metricInfo, err := agent.CollectMetricInfo()
 	assert.NoError(t, err, "CollectMetricInfo should not return an error")
 	assert.NotNil(t, metricInfo, "MetricInfo should not be nil")
+	// a single sample has no previous one to compute CPU usage from
+	assert.Nil(t, metricInfo.CPU)
 }
 
 // TestCollectDiskMetric verifies that CollectDiskMetric returns disk metrics without error
@@ -36,3 +43,69 @@ func TestCollectNetMetric(t *testing.T) {
 	assert.NoError(t, err, "CollectNetMetric should not return an error")
 	assert.NotNil(t, netMetric, "NetMetric should not be nil")
 }
+
+// TestCollectDiskIOMetric verifies that CollectDiskIOMetric returns block device counters without error
+func TestCollectDiskIOMetric(t *testing.T) {
+	diskIO, err := agent.CollectDiskIOMetric(context.Background(), "testdata/sys")
+	assert.NoError(t, err, "CollectDiskIOMetric should not return an error")
+	assert.NotNil(t, diskIO, "DiskIOMetric should not be nil")
+	for _, d := range diskIO {
+		assert.False(t, agent.IsVirtualBlockDevice(d.Device), d.Device)
+		assert.Equal(t, agent.BlockDeviceKind(filepath.Join("testdata/sys/class/block", d.Device)), d.Kind, d.Device)
+	}
+}
+
+// TestBlockDeviceKind verifies disk, partition and stacked devices against a sysfs fixture
+func TestBlockDeviceKind(t *testing.T) {
+	for name, kind := range map[string]string{
+		"sda":     models.BlockDisk,
+		"sda1":    models.BlockPartition,
+		"sda2":    models.BlockPartition,
+		"dm-0":    models.BlockStacked,
+		"loop0":   models.BlockDisk,
+		"nvme0n1": "",
+	} {
+		assert.Equal(t, kind, agent.BlockDeviceKind(filepath.Join("testdata/sys/class/block", name)), name)
+	}
+
+	// loop devices have no storage of their own and are not reported
+	assert.True(t, agent.IsVirtualBlockDevice("loop0"))
+	assert.False(t, agent.IsVirtualBlockDevice("dm-0"))
+}
+
+// TestCollectDiskMetricInodes verifies that inode counts are consistent for every reported filesystem
+func TestCollectDiskMetricInodes(t *testing.T) {
+	diskMetric, err := agent.CollectDiskMetric()
+	assert.NoError(t, err, "CollectDiskMetric should not return an error")
+	for _, d := range diskMetric {
+		assert.NoError(t, d.Validate(), "DiskMetric should be valid")
+		assert.LessOrEqual(t, d.InodesUsed, d.InodesTotal, "used inodes should not exceed total inodes")
+	}
+}
+
+// TestCollectMetricInfoMemory verifies that the memory breakdown is filled in and consistent
+func TestCollectMetricInfoMemory(t *testing.T) {
+	metricInfo, err := agent.CollectMetricInfo()
+	assert.NoError(t, err, "CollectMetricInfo should not return an error")
+	if assert.NotNil(t, metricInfo.Memory, "Memory should not be nil") {
+		assert.NotZero(t, metricInfo.Memory.Total, "total memory should not be zero")
+		assert.LessOrEqual(t, metricInfo.Memory.Available, metricInfo.Memory.Total, "available memory should not exceed total")
+		assert.LessOrEqual(t, metricInfo.Memory.SwapUsed, metricInfo.Memory.SwapTotal, "used swap should not exceed total")
+	}
+}
+
+// TestReadMachineID verifies the first usable machine ID file wins
+func TestReadMachineID(t *testing.T) {
+	dir := t.TempDir()
+	empty := filepath.Join(dir, "empty")
+	uninitialized := filepath.Join(dir, "uninitialized")
+	machineID := filepath.Join(dir, "machine-id")
+	require.NoError(t, os.WriteFile(empty, []byte("\n"), 0o644))
+	require.NoError(t, os.WriteFile(uninitialized, []byte("uninitialized\n"), 0o644))
+	require.NoError(t, os.WriteFile(machineID, []byte("4c4c4544003910528042b4c04f4e3732\n"), 0o644))
+
+	id := agent.ReadMachineID(filepath.Join(dir, "missing"), empty, uninitialized, machineID)
+	assert.Equal(t, "4c4c4544003910528042b4c04f4e3732", id)
+
+	assert.Empty(t, agent.ReadMachineID(filepath.Join(dir, "missing")))
+}
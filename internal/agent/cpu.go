@@ -0,0 +1,155 @@
+package agent
+
+import (
+	"context"
+	"data_agent/internal/models"
+	"errors"
+	"math"
+	"runtime"
+	"sync"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/load"
+)
+
+// collects aggregated and per-core CPU usage with a time breakdown
+// from counter deltas between two consecutive samples
+type cpuCollector struct {
+	mu        sync.Mutex
+	prevTotal cpu.TimesStat
+	prevCores []cpu.TimesStat
+	primed    bool
+}
+
+func (c *cpuCollector) Name() string { return "cpu" }
+
+func (c *cpuCollector) Collect(ctx context.Context) (Apply, error) {
+	total, cores, err := cpuTimes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	prevTotal, prevCores, primed := c.prevTotal, c.prevCores, c.primed
+	c.prevTotal, c.prevCores, c.primed = total, cores, true
+
+	// usage needs two samples, against zero counters it would be the average since boot
+	if !primed {
+		return nil, nil
+	}
+	usage := CPUPercent(prevTotal, total)
+	breakdown := CPUTimesPercent(prevTotal, total)
+
+	// cores without counters of the previous sample, e.g. after CPU hotplug,
+	// have no usage yet and per-core values resume with the next sample
+	var perCore []float64
+	if sameCores(prevCores, cores) {
+		perCore = make([]float64, len(cores))
+		for i := range cores {
+			perCore[i] = CPUPercent(prevCores[i], cores[i])
+		}
+	}
+
+	return func(m *models.Metric) {
+		m.CPU = &usage
+		m.CPUCores = perCore
+		m.CPUTimes = &breakdown
+	}, nil
+}
+
+// both samples list the same cores in the same order
+func sameCores(prev, cur []cpu.TimesStat) bool {
+	if len(prev) != len(cur) {
+		return false
+	}
+	for i := range cur {
+		if prev[i].CPU != cur[i].CPU {
+			return false
+		}
+	}
+	return true
+}
+
+// read aggregated and per-core CPU counters
+func cpuTimes(ctx context.Context) (cpu.TimesStat, []cpu.TimesStat, error) {
+	total, err := cpu.TimesWithContext(ctx, false)
+	if err != nil {
+		return cpu.TimesStat{}, nil, err
+	}
+	if len(total) == 0 {
+		return cpu.TimesStat{}, nil, errors.New("cpu.Times returned no data")
+	}
+	cores, err := cpu.TimesWithContext(ctx, true)
+	if err != nil {
+		return cpu.TimesStat{}, nil, err
+	}
+	return total[0], cores, nil
+}
+
+// busy CPU share in percent between two counter snapshots
+func CPUPercent(prev, cur cpu.TimesStat) float64 {
+	prevTotal, prevBusy := cpuBusy(prev)
+	curTotal, curBusy := cpuBusy(cur)
+
+	if curTotal <= prevTotal || curBusy <= prevBusy {
+		return 0
+	}
+	return clampPercent((curBusy - prevBusy) / (curTotal - prevTotal) * 100)
+}
+
+// share of CPU time per state in percent between two counter snapshots
+func CPUTimesPercent(prev, cur cpu.TimesStat) models.CPUTimes {
+	prevTotal, _ := cpuBusy(prev)
+	curTotal, _ := cpuBusy(cur)
+	total := curTotal - prevTotal
+	if total <= 0 {
+		return models.CPUTimes{}
+	}
+
+	share := func(a, b float64) float64 {
+		return clampPercent((b - a) / total * 100)
+	}
+	return models.CPUTimes{
+		User:    share(prev.User, cur.User),
+		System:  share(prev.System, cur.System),
+		Idle:    share(prev.Idle, cur.Idle),
+		Nice:    share(prev.Nice, cur.Nice),
+		IOWait:  share(prev.Iowait, cur.Iowait),
+		IRQ:     share(prev.Irq, cur.Irq),
+		SoftIRQ: share(prev.Softirq, cur.Softirq),
+		Steal:   share(prev.Steal, cur.Steal),
+	}
+}
+
+// total and busy CPU seconds of a snapshot
+func cpuBusy(t cpu.TimesStat) (float64, float64) {
+	total := t.User + t.System + t.Idle + t.Nice + t.Iowait + t.Irq + t.Softirq + t.Steal
+	// guest time is already counted in user time on Linux
+	if runtime.GOOS != "linux" {
+		total += t.Guest + t.GuestNice
+	}
+	return total, total - t.Idle - t.Iowait
+}
+
+// limit a value to the 0-100 range
+func clampPercent(v float64) float64 {
+	return math.Min(100, math.Max(0, v))
+}
+
+// collects 1, 5 and 15 minute load averages
+type loadCollector struct{}
+
+func (loadCollector) Name() string { return "load" }
+
+func (loadCollector) Collect(ctx context.Context) (Apply, error) {
+	avg, err := load.AvgWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if avg == nil {
+		return nil, errors.New("load.Avg returned nil")
+	}
+	loadAvg := models.LoadAvg{Load1: avg.Load1, Load5: avg.Load5, Load15: avg.Load15}
+	return func(m *models.Metric) { m.Load = &loadAvg }, nil
+}
@@ -0,0 +1,74 @@
+package agent_test
+
+import (
+	"context"
+	"data_agent/internal/agent"
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCPUPercent verifies busy share is computed from counter deltas
+func TestCPUPercent(t *testing.T) {
+	prev := cpu.TimesStat{User: 100, System: 50, Idle: 800, Iowait: 50}
+	cur := cpu.TimesStat{User: 130, System: 60, Idle: 850, Iowait: 60}
+
+	// 40 busy seconds out of 100
+	assert.InDelta(t, 40.0, agent.CPUPercent(prev, cur), 0.001)
+}
+
+// TestCPUPercentCounterReset ensures decreasing counters do not produce negative usage
+func TestCPUPercentCounterReset(t *testing.T) {
+	prev := cpu.TimesStat{User: 500, Idle: 500}
+	cur := cpu.TimesStat{User: 10, Idle: 10}
+
+	assert.Zero(t, agent.CPUPercent(prev, cur))
+}
+
+// TestCPUTimesPercent checks the per-state breakdown sums to the whole interval
+func TestCPUTimesPercent(t *testing.T) {
+	prev := cpu.TimesStat{}
+	cur := cpu.TimesStat{User: 50, System: 20, Idle: 20, Iowait: 5, Steal: 5}
+
+	times := agent.CPUTimesPercent(prev, cur)
+
+	assert.InDelta(t, 50.0, times.User, 0.001)
+	assert.InDelta(t, 20.0, times.System, 0.001)
+	assert.InDelta(t, 20.0, times.Idle, 0.001)
+	assert.InDelta(t, 5.0, times.IOWait, 0.001)
+	assert.InDelta(t, 5.0, times.Steal, 0.001)
+}
+
+// TestCPUCollectorDoesNotBlock ensures CPU sampling uses counter deltas instead of sleeping
+func TestCPUCollectorDoesNotBlock(t *testing.T) {
+	r, err := agent.NewRegistryFromConfig(&agent.Config{})
+	require.NoError(t, err)
+	for _, name := range r.Enabled() {
+		if name != "cpu" {
+			require.NoError(t, r.SetEnabled(name, false))
+		}
+	}
+
+	// the first sample has no previous counters to measure usage against
+	metric := r.Collect(context.Background())
+	assert.Empty(t, metric.Errors)
+	assert.Nil(t, metric.CPU)
+	assert.Nil(t, metric.CPUTimes)
+	assert.Nil(t, metric.CPUCores)
+
+	for i := 0; i < 2; i++ {
+		start := time.Now()
+		metric := r.Collect(context.Background())
+
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+		assert.Empty(t, metric.Errors)
+		assert.NotNil(t, metric.CPUTimes)
+		require.NotNil(t, metric.CPU)
+		assert.GreaterOrEqual(t, *metric.CPU, 0.0)
+		assert.LessOrEqual(t, *metric.CPU, 100.0)
+		assert.NotEmpty(t, metric.CPUCores)
+	}
+}
@@ -0,0 +1,120 @@
+package agent
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/shirou/gopsutil/v3/disk"
+)
+
+// pseudo and virtual filesystems without useful space usage
+var defaultExcludeFSTypes = []string{
+	"autofs", "binfmt_misc", "bpf", "cgroup", "cgroup2", "configfs", "debugfs",
+	"devpts", "devtmpfs", "efivarfs", "fuse.lxcfs", "fusectl", "hugetlbfs", "mqueue",
+	"nsfs", "overlay", "proc", "pstore", "ramfs", "rpc_pipefs", "securityfs",
+	"selinuxfs", "squashfs", "sysfs", "tmpfs", "tracefs",
+}
+
+// mountpoints of kernel and runtime trees, a trailing /* matches the whole subtree
+var defaultExcludeMounts = []string{"/proc/*", "/sys/*", "/dev/*", "/run/*", "/snap/*", "/var/lib/docker/*"}
+
+// loop devices back snap packages and images
+var defaultExcludeDevices = []string{"/dev/loop*"}
+
+// include and exclude rules for partitions reported by the disk collector
+type DiskFilter struct {
+	IncludeFSTypes []string `yaml:"include_fstypes"`
+	ExcludeFSTypes []string `yaml:"exclude_fstypes"`
+	IncludeMounts  []string `yaml:"include_mounts"`
+	ExcludeMounts  []string `yaml:"exclude_mounts"`
+	IncludeDevices []string `yaml:"include_devices"`
+	ExcludeDevices []string `yaml:"exclude_devices"`
+}
+
+// filter that drops virtual filesystems
+func DefaultDiskFilter() DiskFilter {
+	return DiskFilter{
+		ExcludeFSTypes: append([]string(nil), defaultExcludeFSTypes...),
+		ExcludeMounts:  append([]string(nil), defaultExcludeMounts...),
+		ExcludeDevices: append([]string(nil), defaultExcludeDevices...),
+	}
+}
+
+// check that all glob patterns are well formed
+func (f DiskFilter) Validate() error {
+	for _, pattern := range append(append(append(append([]string(nil),
+		f.IncludeMounts...), f.ExcludeMounts...), f.IncludeDevices...), f.ExcludeDevices...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid disk filter pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// keep partitions matching the rules, one mountpoint per block device
+func (f DiskFilter) Select(partitions []disk.PartitionStat) []disk.PartitionStat {
+	var selected []disk.PartitionStat
+	byDevice := make(map[string]int)
+	for _, p := range partitions {
+		if !f.allowed(p) {
+			continue
+		}
+
+		// bind mounts of the same device report the same usage, keep the shortest path
+		if strings.HasPrefix(p.Device, "/") {
+			if i, ok := byDevice[p.Device]; ok {
+				if len(p.Mountpoint) < len(selected[i].Mountpoint) {
+					selected[i] = p
+				}
+				continue
+			}
+			byDevice[p.Device] = len(selected)
+		}
+		selected = append(selected, p)
+	}
+	return selected
+}
+
+// check a partition against include and exclude rules
+func (f DiskFilter) allowed(p disk.PartitionStat) bool {
+	rules := []struct {
+		include, exclude []string
+		value            string
+		match            func(pattern, value string) bool
+	}{
+		{f.IncludeFSTypes, f.ExcludeFSTypes, p.Fstype, strings.EqualFold},
+		{f.IncludeMounts, f.ExcludeMounts, p.Mountpoint, matchGlob},
+		{f.IncludeDevices, f.ExcludeDevices, p.Device, matchGlob},
+	}
+	for _, r := range rules {
+		if len(r.include) > 0 && !matchAny(r.include, r.value, r.match) {
+			return false
+		}
+		if matchAny(r.exclude, r.value, r.match) {
+			return false
+		}
+	}
+	return true
+}
+
+// report whether any pattern matches the value
+func matchAny(patterns []string, value string, match func(pattern, value string) bool) bool {
+	for _, pattern := range patterns {
+		if match(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// shell glob match where a trailing /* also matches nested paths
+func matchGlob(pattern, value string) bool {
+	if ok, err := path.Match(pattern, value); err == nil && ok {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(pattern, "/*"); ok {
+		return strings.HasPrefix(value, prefix+"/")
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package agent_test
+
+import (
+	"data_agent/internal/agent"
+	"testing"
+
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/stretchr/testify/assert"
+)
+
+// mountpoints of the selected partitions
+func mountpoints(partitions []disk.PartitionStat) []string {
+	var paths []string
+	for _, p := range partitions {
+		paths = append(paths, p.Mountpoint)
+	}
+	return paths
+}
+
+// TestDefaultDiskFilterDropsVirtualFilesystems verifies the defaults keep only real filesystems
+func TestDefaultDiskFilterDropsVirtualFilesystems(t *testing.T) {
+	partitions := []disk.PartitionStat{
+		{Device: "/dev/sda1", Mountpoint: "/", Fstype: "ext4"},
+		{Device: "proc", Mountpoint: "/proc", Fstype: "proc"},
+		{Device: "sysfs", Mountpoint: "/sys", Fstype: "sysfs"},
+		{Device: "tmpfs", Mountpoint: "/run", Fstype: "tmpfs"},
+		{Device: "cgroup2", Mountpoint: "/sys/fs/cgroup", Fstype: "cgroup2"},
+		{Device: "overlay", Mountpoint: "/var/lib/docker/overlay2/abc/merged", Fstype: "overlay"},
+		{Device: "/dev/loop3", Mountpoint: "/snap/core/123", Fstype: "squashfs"},
+		{Device: "/dev/sdb1", Mountpoint: "/data", Fstype: "xfs"},
+	}
+
+	selected := agent.DefaultDiskFilter().Select(partitions)
+
+	assert.Equal(t, []string{"/", "/data"}, mountpoints(selected))
+}
+
+// TestDiskFilterDeduplicatesBindMounts ensures bind mounts of one device are reported once
+func TestDiskFilterDeduplicatesBindMounts(t *testing.T) {
+	partitions := []disk.PartitionStat{
+		{Device: "/dev/sdb1", Mountpoint: "/srv/data/export", Fstype: "ext4"},
+		{Device: "/dev/sdb1", Mountpoint: "/data", Fstype: "ext4"},
+		{Device: "server:/share", Mountpoint: "/mnt/share", Fstype: "nfs4"},
+	}
+
+	selected := agent.DefaultDiskFilter().Select(partitions)
+
+	assert.Equal(t, []string{"/data", "/mnt/share"}, mountpoints(selected))
+}
+
+// TestDiskFilterIncludeRules checks that include rules restrict the selection
+func TestDiskFilterIncludeRules(t *testing.T) {
+	partitions := []disk.PartitionStat{
+		{Device: "/dev/sda1", Mountpoint: "/", Fstype: "ext4"},
+		{Device: "/dev/sdb1", Mountpoint: "/data", Fstype: "xfs"},
+		{Device: "/dev/sdc1", Mountpoint: "/data/archive", Fstype: "xfs"},
+		{Device: "tmpfs", Mountpoint: "/scratch", Fstype: "tmpfs"},
+	}
+
+	filter := agent.DiskFilter{IncludeFSTypes: []string{"xfs", "tmpfs"}, IncludeMounts: []string{"/data/*", "/scratch"}}
+
+	assert.Equal(t, []string{"/data/archive", "/scratch"}, mountpoints(filter.Select(partitions)))
+}
+
+// TestDiskFilterValidate verifies malformed glob patterns are rejected
+func TestDiskFilterValidate(t *testing.T) {
+	assert.NoError(t, agent.DefaultDiskFilter().Validate())
+	assert.Error(t, agent.DiskFilter{ExcludeMounts: []string{"/data/["}}.Validate())
+}
@@ -0,0 +1,121 @@
+package agent
+
+import (
+	"context"
+	"data_agent/internal/models"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/shirou/gopsutil/v3/host"
+)
+
+// virtual block devices without real storage behind them
+var virtualBlockDevices = []string{"loop", "ram", "zram"}
+
+// collects I/O counters per block device with rates since the previous sample
+type diskIOCollector struct {
+	sysRoot  string
+	mu       sync.Mutex
+	prev     map[string]models.DiskIOMetric
+	prevTime time.Time
+	bootTime uint64
+}
+
+func (c *diskIOCollector) Name() string { return "diskio" }
+
+func (c *diskIOCollector) Collect(ctx context.Context) (Apply, error) {
+	diskIO, err := CollectDiskIOMetric(ctx, c.sysRoot)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	bootTime, err := host.BootTimeWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// counters restart from zero after a reboot
+	if bootTime != c.bootTime {
+		c.prev = nil
+	}
+	elapsed := now.Sub(c.prevTime)
+	for i, cur := range diskIO {
+		if prev, ok := c.prev[cur.Device]; ok {
+			diskIO[i].Rates = DiskIOMetricRates(prev, cur, elapsed)
+		}
+	}
+
+	c.prev = make(map[string]models.DiskIOMetric, len(diskIO))
+	for _, m := range diskIO {
+		c.prev[m.Device] = m
+	}
+	c.prevTime = now
+	c.bootTime = bootTime
+
+	return func(m *models.Metric) { m.DiskIO = diskIO }, nil
+}
+
+// collect I/O counters of physical block devices, each marked as disk, partition or stacked device
+// so that summing over disks counts every I/O once
+func CollectDiskIOMetric(ctx context.Context, sysRoot string) ([]models.DiskIOMetric, error) {
+	counters, err := disk.IOCountersWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := []models.DiskIOMetric{}
+	for name, c := range counters {
+		if isVirtualBlockDevice(name) {
+			continue
+		}
+		metrics = append(metrics, models.DiskIOMetric{
+			Device:       name,
+			Kind:         blockDeviceKind(filepath.Join(sysRoot, "class", "block", name)),
+			ReadCount:    c.ReadCount,
+			WriteCount:   c.WriteCount,
+			ReadBytes:    c.ReadBytes,
+			WriteBytes:   c.WriteBytes,
+			ReadTimeMs:   c.ReadTime,
+			WriteTimeMs:  c.WriteTime,
+			IOTimeMs:     c.IoTime,
+			WeightedIOMs: c.WeightedIO,
+			InProgress:   c.IopsInProgress,
+		})
+	}
+
+	// map iteration order is random, keep samples stable
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].Device < metrics[j].Device })
+	return metrics, nil
+}
+
+// kind of a block device from its sysfs directory, empty when sysfs does not know the device
+func blockDeviceKind(dir string) string {
+	if _, err := os.Stat(dir); err != nil {
+		return ""
+	}
+	if _, err := os.Stat(filepath.Join(dir, "partition")); err == nil {
+		return models.BlockPartition
+	}
+	if slaves, err := os.ReadDir(filepath.Join(dir, "slaves")); err == nil && len(slaves) > 0 {
+		return models.BlockStacked
+	}
+	return models.BlockDisk
+}
+
+// report whether a block device is virtual
+func isVirtualBlockDevice(name string) bool {
+	for _, prefix := range virtualBlockDevices {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,414 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"data_agent/internal/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultExecTimeout  = 10 * time.Second
+	defaultExecInterval = time.Minute
+	// output beyond this size is discarded
+	maxExecOutput = 64 << 10
+)
+
+// output formats of exec plugins
+const (
+	ExecFormatJSON   = "json"
+	ExecFormatNagios = "nagios"
+)
+
+// plugin statuses, Nagios plugins report the state from their exit code
+const (
+	PluginOK       = "ok"
+	PluginWarning  = "warning"
+	PluginCritical = "critical"
+	PluginUnknown  = "unknown"
+	PluginError    = "error"
+)
+
+var pluginNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.\-]+$`)
+
+// command run periodically to produce custom metrics
+type ExecPlugin struct {
+	Name    string
+	Command []string
+	Format  string
+	Timeout time.Duration
+	// zero runs the plugin for every sample
+	Interval time.Duration
+}
+
+// validation of plugin settings
+func (p *ExecPlugin) Validate() error {
+	if !pluginNamePattern.MatchString(p.Name) {
+		return fmt.Errorf("invalid plugin name %q: expected letters, digits, '_', '.' or '-'", p.Name)
+	}
+	if len(p.Command) == 0 {
+		return fmt.Errorf("plugin %q has no command", p.Name)
+	}
+	if p.Format != ExecFormatJSON && p.Format != ExecFormatNagios {
+		return fmt.Errorf("plugin %q has unknown format %q, expected %s or %s", p.Name, p.Format, ExecFormatJSON, ExecFormatNagios)
+	}
+	if p.Timeout <= 0 || p.Interval < 0 {
+		return fmt.Errorf("plugin %q needs a positive timeout and a non-negative interval", p.Name)
+	}
+	return nil
+}
+
+// parse a plugin given as name=format:command args
+func ParseExecPlugin(value string, timeout, interval time.Duration) (ExecPlugin, error) {
+	p, err := splitExecPlugin(value)
+	if err != nil {
+		return ExecPlugin{}, err
+	}
+	p.Timeout = timeout
+	p.Interval = interval
+	if err := p.Validate(); err != nil {
+		return ExecPlugin{}, err
+	}
+	return p, nil
+}
+
+// split name=format:command args without validating the plugin
+func splitExecPlugin(value string) (ExecPlugin, error) {
+	name, rest, ok := strings.Cut(value, "=")
+	format, command, ok2 := strings.Cut(rest, ":")
+	if !ok || !ok2 {
+		return ExecPlugin{}, fmt.Errorf("invalid plugin %q: expected name=format:command", value)
+	}
+	return ExecPlugin{
+		Name:    strings.TrimSpace(name),
+		Command: strings.Fields(command),
+		Format:  strings.TrimSpace(format),
+	}, nil
+}
+
+// runs plugins in the background and attaches finished results to the next sample
+type execCollector struct {
+	mu      sync.Mutex
+	plugins []ExecPlugin
+	next    map[string]time.Time
+	running map[string]bool
+	done    []models.PluginResult
+}
+
+func newExecCollector(cfg *Config) (*execCollector, error) {
+	names := make(map[string]bool)
+	for _, p := range cfg.ExecPlugins {
+		if err := p.Validate(); err != nil {
+			return nil, err
+		}
+		if names[p.Name] {
+			return nil, fmt.Errorf("plugin %q is configured twice", p.Name)
+		}
+		names[p.Name] = true
+	}
+	return &execCollector{
+		plugins: cfg.ExecPlugins,
+		next:    make(map[string]time.Time),
+		running: make(map[string]bool),
+	}, nil
+}
+
+func (c *execCollector) Name() string { return "exec" }
+
+func (c *execCollector) Collect(ctx context.Context) (Apply, error) {
+	now := time.Now()
+
+	c.mu.Lock()
+	var started []chan struct{}
+	for _, p := range c.plugins {
+		if c.running[p.Name] || now.Before(c.next[p.Name]) {
+			continue
+		}
+		c.running[p.Name] = true
+		c.next[p.Name] = now.Add(p.Interval)
+		done := make(chan struct{})
+		started = append(started, done)
+		go c.run(p, done)
+	}
+	c.mu.Unlock()
+
+	// plugins slower than the collector timeout are reported with a later sample
+wait:
+	for _, done := range started {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			break wait
+		}
+	}
+
+	c.mu.Lock()
+	results := c.done
+	c.done = nil
+	c.mu.Unlock()
+
+	if len(results) == 0 {
+		return nil, nil
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Name < results[j].Name })
+	return func(m *models.Metric) { m.Plugins = results }, nil
+}
+
+// run a plugin outside of the sample context so it is bounded by its own timeout only
+func (c *execCollector) run(p ExecPlugin, done chan<- struct{}) {
+	result := RunPlugin(context.Background(), p)
+
+	c.mu.Lock()
+	c.done = append(c.done, result)
+	c.running[p.Name] = false
+	c.mu.Unlock()
+	close(done)
+}
+
+// run a plugin command and parse its output
+func RunPlugin(ctx context.Context, p ExecPlugin) models.PluginResult {
+	ctx, cancel := context.WithTimeout(ctx, p.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	result := models.PluginResult{Name: p.Name, Time: start}
+
+	var stdout, stderr limitedBuffer
+	cmd := exec.CommandContext(ctx, p.Command[0], p.Command[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// do not wait forever for children that keep the output open
+	cmd.WaitDelay = time.Second
+	err := cmd.Run()
+	result.DurationMs = float64(time.Since(start).Microseconds()) / 1000
+
+	exitCode := 0
+	var exitErr *exec.ExitError
+	switch {
+	case ctx.Err() != nil:
+		result.Status = PluginError
+		result.Error = fmt.Sprintf("timed out after %s", p.Timeout)
+		return result
+	case errors.As(err, &exitErr):
+		exitCode = exitErr.ExitCode()
+	case err != nil:
+		result.Status = PluginError
+		result.Error = err.Error()
+		return result
+	}
+
+	switch p.Format {
+	case ExecFormatNagios:
+		result.Status, result.Message, result.Metrics, err = ParseNagiosOutput(stdout.Bytes(), exitCode)
+	default:
+		if exitCode != 0 {
+			result.Status = PluginError
+			result.Error = fmt.Sprintf("exit status %d: %s", exitCode, strings.TrimSpace(stderr.String()))
+			return result
+		}
+		result.Status = PluginOK
+		result.Metrics, err = ParseJSONOutput(stdout.Bytes())
+	}
+	if err != nil {
+		result.Status = PluginError
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// parse a JSON object of numbers, nested objects are flattened with dots
+func ParseJSONOutput(data []byte) ([]models.CustomMetric, error) {
+	var doc map[string]any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decode json output: %w", err)
+	}
+
+	var metrics []models.CustomMetric
+	if err := flattenJSON("", doc, &metrics); err != nil {
+		return nil, err
+	}
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].Name < metrics[j].Name })
+	return metrics, nil
+}
+
+// collect numeric leaves of a decoded JSON object
+func flattenJSON(prefix string, doc map[string]any, metrics *[]models.CustomMetric) error {
+	for key, raw := range doc {
+		name := key
+		if prefix != "" {
+			name = prefix + "." + key
+		}
+		switch v := raw.(type) {
+		case json.Number:
+			value, err := v.Float64()
+			if err != nil {
+				return fmt.Errorf("invalid value of %s: %w", name, err)
+			}
+			*metrics = append(*metrics, models.CustomMetric{Name: name, Value: value})
+		case bool:
+			value := 0.0
+			if v {
+				value = 1
+			}
+			*metrics = append(*metrics, models.CustomMetric{Name: name, Value: value})
+		case map[string]any:
+			if err := flattenJSON(name, v, metrics); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("value of %s is not a number", name)
+		}
+	}
+	return nil
+}
+
+// parse Nagios plugin output "TEXT | perfdata" with the state taken from the exit code
+func ParseNagiosOutput(output []byte, exitCode int) (string, string, []models.CustomMetric, error) {
+	status := PluginUnknown
+	switch exitCode {
+	case 0:
+		status = PluginOK
+	case 1:
+		status = PluginWarning
+	case 2:
+		status = PluginCritical
+	}
+
+	// perfdata may follow a pipe on the first line, the first pipe in the long text
+	// starts perfdata that runs to the end of the output
+	var message string
+	var perfdata []string
+	inLongPerf := false
+	for i, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		perf := line
+		if !inLongPerf {
+			var text string
+			var found bool
+			text, perf, found = strings.Cut(line, "|")
+			if i == 0 {
+				message = strings.TrimSpace(text)
+			} else {
+				inLongPerf = found
+			}
+		}
+		if perf = strings.TrimSpace(perf); perf != "" {
+			perfdata = append(perfdata, perf)
+		}
+	}
+
+	var metrics []models.CustomMetric
+	for _, perf := range perfdata {
+		parsed, err := parsePerfdata(perf)
+		if err != nil {
+			return status, message, nil, err
+		}
+		metrics = append(metrics, parsed...)
+	}
+	return status, message, metrics, nil
+}
+
+// parse space-separated 'label'=value[UOM];warn;crit;min;max items
+func parsePerfdata(perf string) ([]models.CustomMetric, error) {
+	var metrics []models.CustomMetric
+	for rest := strings.TrimSpace(perf); rest != ""; rest = strings.TrimSpace(rest) {
+		var label string
+		if strings.HasPrefix(rest, "'") {
+			// quoted labels may contain spaces, '' is an escaped quote
+			end := 1
+			for {
+				i := strings.IndexByte(rest[end:], '\'')
+				if i < 0 {
+					return nil, fmt.Errorf("unterminated label in perfdata %q", perf)
+				}
+				end += i
+				if end+1 < len(rest) && rest[end+1] == '\'' {
+					end += 2
+					continue
+				}
+				break
+			}
+			label = strings.ReplaceAll(rest[1:end], "''", "'")
+			rest = rest[end+1:]
+			if !strings.HasPrefix(rest, "=") {
+				return nil, fmt.Errorf("missing value of %q in perfdata", label)
+			}
+		} else {
+			i := strings.IndexByte(rest, '=')
+			if i <= 0 {
+				return nil, fmt.Errorf("invalid perfdata %q", rest)
+			}
+			label, rest = rest[:i], rest[i:]
+		}
+
+		item, remainder, _ := strings.Cut(rest[1:], " ")
+		rest = remainder
+		fields := strings.Split(item, ";")
+
+		// "U" marks a value the plugin could not determine
+		if fields[0] == "U" {
+			continue
+		}
+		value, unit := splitUnit(fields[0])
+		v, err := strconv.ParseFloat(value, 64)
+		// NaN and infinities cannot be encoded as JSON
+		if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, fmt.Errorf("invalid value of %q in perfdata: %q", label, fields[0])
+		}
+		m := models.CustomMetric{Name: label, Value: v, Unit: unit}
+		if len(fields) > 1 {
+			m.Warn = parseThreshold(fields[1])
+		}
+		if len(fields) > 2 {
+			m.Crit = parseThreshold(fields[2])
+		}
+		metrics = append(metrics, m)
+	}
+	return metrics, nil
+}
+
+// split a perfdata value into number and unit of measurement
+func splitUnit(value string) (string, string) {
+	i := strings.LastIndexAny(value, "0123456789.")
+	if i < 0 {
+		return value, ""
+	}
+	return value[:i+1], value[i+1:]
+}
+
+// upper bound of a threshold range, nil when it is empty or not a plain number
+func parseThreshold(raw string) *float64 {
+	// ranges like 10:20 or @10:20 keep only their upper bound
+	if i := strings.LastIndexByte(raw, ':'); i >= 0 {
+		raw = raw[i+1:]
+	}
+	v, err := strconv.ParseFloat(strings.TrimPrefix(raw, "@"), 64)
+	// infinite thresholds cannot be encoded as JSON either
+	if err != nil || math.IsInf(v, 0) || math.IsNaN(v) {
+		return nil
+	}
+	return &v
+}
+
+// buffer that keeps at most maxExecOutput bytes
+type limitedBuffer struct {
+	bytes.Buffer
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := maxExecOutput - b.Len(); room > 0 {
+		b.Buffer.Write(p[:min(len(p), room)])
+	}
+	return len(p), nil
+}
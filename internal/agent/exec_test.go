@@ -0,0 +1,142 @@
+package agent_test
+
+import (
+	"context"
+	"data_agent/internal/agent"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeScript creates an executable shell script in a temporary directory
+func writeScript(t *testing.T, body string) string {
+	path := filepath.Join(t.TempDir(), "plugin.sh")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0o755))
+	return path
+}
+
+// TestParseJSONOutput verifies numbers, booleans and nested objects are turned into metrics
+func TestParseJSONOutput(t *testing.T) {
+	metrics, err := agent.ParseJSONOutput([]byte(`{"queue_depth": 42, "replication": {"lag_seconds": 1.5}, "healthy": true}`))
+	require.NoError(t, err)
+	require.Len(t, metrics, 3)
+
+	assert.Equal(t, "healthy", metrics[0].Name)
+	assert.Equal(t, 1.0, metrics[0].Value)
+	assert.Equal(t, "queue_depth", metrics[1].Name)
+	assert.Equal(t, 42.0, metrics[1].Value)
+	assert.Equal(t, "replication.lag_seconds", metrics[2].Name)
+	assert.Equal(t, 1.5, metrics[2].Value)
+
+	_, err = agent.ParseJSONOutput([]byte(`{"state": "up"}`))
+	assert.Error(t, err)
+	_, err = agent.ParseJSONOutput([]byte(`not json`))
+	assert.Error(t, err)
+}
+
+// TestParseNagiosOutput checks status, message and perfdata parsing
+func TestParseNagiosOutput(t *testing.T) {
+	output := "DISK WARNING - free space: / 3326 MB (56%) | /=2643MB;5948;5958;0;5968\n" +
+		"/ 15272 MB (77%);\n" +
+		"/boot 68 MB (69%); | 'boot usage'=69%;80;90 'it''s'=U\n" +
+		"inodes=12%;80;90\n"
+
+	status, message, metrics, err := agent.ParseNagiosOutput([]byte(output), 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, agent.PluginWarning, status)
+	assert.Equal(t, "DISK WARNING - free space: / 3326 MB (56%)", message)
+	require.Len(t, metrics, 3)
+	assert.Equal(t, "/", metrics[0].Name)
+	assert.Equal(t, 2643.0, metrics[0].Value)
+	assert.Equal(t, "MB", metrics[0].Unit)
+	require.NotNil(t, metrics[0].Crit)
+	assert.Equal(t, 5958.0, *metrics[0].Crit)
+	assert.Equal(t, "boot usage", metrics[1].Name)
+	assert.Equal(t, "%", metrics[1].Unit)
+	require.NotNil(t, metrics[1].Warn)
+	assert.Equal(t, 80.0, *metrics[1].Warn)
+	assert.Equal(t, "inodes", metrics[2].Name)
+	assert.Equal(t, 12.0, metrics[2].Value)
+
+	status, _, _, err = agent.ParseNagiosOutput([]byte("something odd"), 3)
+	assert.NoError(t, err)
+	assert.Equal(t, agent.PluginUnknown, status)
+
+	_, _, _, err = agent.ParseNagiosOutput([]byte("OK | 'broken=1"), 0)
+	assert.Error(t, err)
+
+	// infinite values cannot be encoded as JSON and would drop the whole sample
+	_, _, _, err = agent.ParseNagiosOutput([]byte("OK | x=inf"), 0)
+	assert.Error(t, err)
+	_, _, _, err = agent.ParseNagiosOutput([]byte("OK | x=-Inf"), 0)
+	assert.Error(t, err)
+
+	// unusable thresholds are dropped, the value itself is kept
+	_, _, metrics, err = agent.ParseNagiosOutput([]byte("OK | x=1;inf;NaN"), 0)
+	require.NoError(t, err)
+	require.Len(t, metrics, 1)
+	assert.Equal(t, 1.0, metrics[0].Value)
+	assert.Nil(t, metrics[0].Warn)
+	assert.Nil(t, metrics[0].Crit)
+}
+
+// TestParseExecPlugin verifies the name=format:command flag syntax
+func TestParseExecPlugin(t *testing.T) {
+	p, err := agent.ParseExecPlugin("queue=json:/usr/local/bin/queue-depth --vhost prod", time.Second, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "queue", p.Name)
+	assert.Equal(t, agent.ExecFormatJSON, p.Format)
+	assert.Equal(t, []string{"/usr/local/bin/queue-depth", "--vhost", "prod"}, p.Command)
+
+	for _, value := range []string{"queue", "queue=json", "queue=xml:/bin/true", "bad name=json:/bin/true", "queue=json:"} {
+		_, err := agent.ParseExecPlugin(value, time.Second, time.Minute)
+		assert.Error(t, err, value)
+	}
+}
+
+// TestRunPlugin runs real scripts in both formats
+func TestRunPlugin(t *testing.T) {
+	ctx := context.Background()
+
+	result := agent.RunPlugin(ctx, agent.ExecPlugin{
+		Name: "app", Format: agent.ExecFormatJSON, Timeout: 5 * time.Second,
+		Command: []string{writeScript(t, `echo '{"queue_depth": 7}'`)},
+	})
+	assert.Equal(t, agent.PluginOK, result.Status)
+	require.Len(t, result.Metrics, 1)
+	assert.Equal(t, 7.0, result.Metrics[0].Value)
+
+	result = agent.RunPlugin(ctx, agent.ExecPlugin{
+		Name: "check", Format: agent.ExecFormatNagios, Timeout: 5 * time.Second,
+		Command: []string{writeScript(t, `echo 'CRITICAL - lag 30s | lag=30s;10;20'; exit 2`)},
+	})
+	assert.Equal(t, agent.PluginCritical, result.Status)
+	assert.Equal(t, "CRITICAL - lag 30s", result.Message)
+	require.Len(t, result.Metrics, 1)
+	assert.Equal(t, "s", result.Metrics[0].Unit)
+
+	result = agent.RunPlugin(ctx, agent.ExecPlugin{
+		Name: "failing", Format: agent.ExecFormatJSON, Timeout: 5 * time.Second,
+		Command: []string{writeScript(t, `echo 'no database' >&2; exit 1`)},
+	})
+	assert.Equal(t, agent.PluginError, result.Status)
+	assert.Contains(t, result.Error, "no database")
+}
+
+// TestRunPluginTimeout ensures a hanging plugin is stopped at its timeout
+func TestRunPluginTimeout(t *testing.T) {
+	start := time.Now()
+	result := agent.RunPlugin(context.Background(), agent.ExecPlugin{
+		Name: "slow", Format: agent.ExecFormatJSON, Timeout: 100 * time.Millisecond,
+		Command: []string{writeScript(t, `sleep 10`)},
+	})
+
+	assert.Equal(t, agent.PluginError, result.Status)
+	assert.Contains(t, result.Error, "timed out")
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
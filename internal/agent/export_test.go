@@ -0,0 +1,45 @@
+package agent
+
+import (
+	"context"
+	"data_agent/internal/queue"
+	"time"
+)
+
+// Runner exposes the reload logic of a running agent to tests
+type Runner struct{ r *runner }
+
+// start a runner like Run does, without collecting on ticks
+func StartRunner(ctx context.Context, cfg *Config, ticker *time.Ticker) (*Runner, error) {
+	registry, err := NewRegistryFromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	spool, err := openSpool(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Runner{r: &runner{cfg: cfg, registry: registry, output: startOutput(ctx, cfg, spool), ticker: ticker}}, nil
+}
+
+func (r *Runner) Reload(ctx context.Context, cfg *Config) error { return r.r.reload(ctx, cfg) }
+
+func (r *Runner) Registry() *Registry { return r.r.registry }
+
+func (r *Runner) Publisher() *queue.Publisher { return r.r.output.publisher }
+
+func (r *Runner) Spool() *queue.Spool { return r.r.output.spool }
+
+// stop the publisher and release collectors and the spool
+func (r *Runner) Stop() {
+	r.r.output.stop()
+	r.r.close()
+}
+
+// Collector returns the running collector of a registry by name
+func (r *Registry) Collector(name string) Collector { return r.collector(name) }
+
+var (
+	BlockDeviceKind      = blockDeviceKind
+	IsVirtualBlockDevice = isVirtualBlockDevice
+)
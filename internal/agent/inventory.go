@@ -0,0 +1,132 @@
+package agent
+
+import (
+	"context"
+	"data_agent/internal/models"
+	"fmt"
+	"reflect"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/host"
+	"github.com/shirou/gopsutil/v3/mem"
+	"github.com/shirou/gopsutil/v3/net"
+)
+
+const (
+	// how often the inventory is collected to detect changes
+	inventoryCheckInterval = time.Minute
+	// how often an unchanged inventory is sent again in case a message was lost
+	inventoryResendInterval = time.Hour
+)
+
+// collect hardware and platform details of the host
+func CollectInventory(ctx context.Context) (*models.HostInventory, error) {
+	info, err := host.InfoWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("host info: %w", err)
+	}
+	inv := &models.HostInventory{
+		BootTime:           time.Unix(int64(info.BootTime), 0).UTC(),
+		Virtualization:     info.VirtualizationSystem,
+		VirtualizationRole: info.VirtualizationRole,
+		Arch:               info.KernelArch,
+	}
+	if inv.Arch == "" {
+		inv.Arch = runtime.GOARCH
+	}
+
+	cpus, err := cpu.InfoWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cpu info: %w", err)
+	}
+	if len(cpus) > 0 {
+		inv.CPUModel = strings.TrimSpace(cpus[0].ModelName)
+	}
+	if inv.CPUCores, err = cpu.CountsWithContext(ctx, false); err != nil {
+		return nil, fmt.Errorf("cpu cores: %w", err)
+	}
+	if inv.CPUThreads, err = cpu.CountsWithContext(ctx, true); err != nil {
+		return nil, fmt.Errorf("cpu threads: %w", err)
+	}
+
+	vm, err := mem.VirtualMemoryWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("memory info: %w", err)
+	}
+	inv.MemoryTotal = vm.Total
+
+	interfaces, err := net.InterfacesWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("network interfaces: %w", err)
+	}
+	for _, iface := range interfaces {
+		// container veth pairs come and go and would report a change every time
+		if slices.Contains(iface.Flags, "loopback") || strings.HasPrefix(iface.Name, "veth") {
+			continue
+		}
+		ni := models.NetInterface{Name: iface.Name, MAC: iface.HardwareAddr}
+		for _, addr := range iface.Addrs {
+			ni.Addrs = append(ni.Addrs, addr.Addr)
+		}
+		inv.Interfaces = append(inv.Interfaces, ni)
+	}
+	sort.Slice(inv.Interfaces, func(i, j int) bool { return inv.Interfaces[i].Name < inv.Interfaces[j].Name })
+
+	return inv, nil
+}
+
+// decides when the host inventory is attached to a message
+type InventoryTracker struct {
+	mu        sync.Mutex
+	collect   func(ctx context.Context) (*models.HostInventory, error)
+	check     time.Duration
+	resend    time.Duration
+	current   *models.HostInventory
+	checkedAt time.Time
+	sentAt    time.Time
+	changed   bool
+}
+
+// create a tracker that collects with the given function every check interval
+// and repeats an unchanged inventory every resend interval
+func NewInventoryTracker(collect func(ctx context.Context) (*models.HostInventory, error), check, resend time.Duration) *InventoryTracker {
+	return &InventoryTracker{collect: collect, check: check, resend: resend}
+}
+
+// inventory to attach to the message sent at now, nil when the last sent one is still current
+func (t *InventoryTracker) Next(ctx context.Context, now time.Time) (*models.HostInventory, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.current == nil || now.Sub(t.checkedAt) >= t.check {
+		inv, err := t.collect(ctx)
+		if err != nil {
+			return nil, err
+		}
+		t.checkedAt = now
+		if !reflect.DeepEqual(inv, t.current) {
+			t.current = inv
+			t.changed = true
+		}
+	}
+
+	if !t.changed && now.Sub(t.sentAt) < t.resend {
+		return nil, nil
+	}
+	return t.current, nil
+}
+
+// mark the inventory returned by Next at now as delivered to the publisher
+func (t *InventoryTracker) Sent(now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.changed = false
+	t.sentAt = now
+}
@@ -0,0 +1,69 @@
+package agent_test
+
+import (
+	"context"
+	"data_agent/internal/agent"
+	"data_agent/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCollectInventory verifies that the inventory of the current host is filled in
+func TestCollectInventory(t *testing.T) {
+	inv, err := agent.CollectInventory(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, inv)
+
+	assert.NotEmpty(t, inv.Arch)
+	assert.Positive(t, inv.CPUThreads)
+	assert.Positive(t, inv.MemoryTotal)
+	assert.False(t, inv.BootTime.IsZero())
+	for _, iface := range inv.Interfaces {
+		assert.NotEqual(t, "lo", iface.Name, "loopback should be skipped")
+	}
+}
+
+// TestInventoryTracker checks that the inventory is sent on start, on change and on resend only
+func TestInventoryTracker(t *testing.T) {
+	ctx := context.Background()
+	memory := uint64(8 << 30)
+	calls := 0
+	tracker := agent.NewInventoryTracker(func(context.Context) (*models.HostInventory, error) {
+		calls++
+		return &models.HostInventory{Arch: "x86_64", MemoryTotal: memory}, nil
+	}, time.Minute, time.Hour)
+
+	start := time.Now()
+	inv, err := tracker.Next(ctx, start)
+	require.NoError(t, err)
+	require.NotNil(t, inv, "first message should carry the inventory")
+	tracker.Sent(start)
+
+	inv, err = tracker.Next(ctx, start.Add(30*time.Second))
+	require.NoError(t, err)
+	assert.Nil(t, inv)
+	assert.Equal(t, 1, calls, "inventory should not be collected before the check interval")
+
+	inv, err = tracker.Next(ctx, start.Add(2*time.Minute))
+	require.NoError(t, err)
+	assert.Nil(t, inv, "unchanged inventory should not be sent")
+
+	memory = 16 << 30
+	inv, err = tracker.Next(ctx, start.Add(4*time.Minute))
+	require.NoError(t, err)
+	require.NotNil(t, inv)
+	assert.Equal(t, uint64(16<<30), inv.MemoryTotal)
+
+	// not marked as sent, so it is offered again
+	inv, err = tracker.Next(ctx, start.Add(4*time.Minute+time.Second))
+	require.NoError(t, err)
+	assert.NotNil(t, inv)
+	tracker.Sent(start.Add(4 * time.Minute))
+
+	inv, err = tracker.Next(ctx, start.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.NotNil(t, inv, "inventory should be resent after the resend interval")
+}
@@ -0,0 +1,421 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"data_agent/internal/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+const (
+	// bytes read from a single file per sample, the rest is read with later samples
+	maxLogReadBytes = 8 << 20
+	// longer lines are cut for matching and forwarding
+	maxLogLineBytes = 64 << 10
+	// forwarded lines are cut to this length
+	maxLogSampleLen = 1024
+)
+
+// named regular expression counted in followed log files
+type LogPattern struct {
+	Name  string `yaml:"name"`
+	Regex string `yaml:"regex"`
+}
+
+// parse a pattern given as name=regex
+func ParseLogPattern(value string) (LogPattern, error) {
+	name, regex, ok := strings.Cut(value, "=")
+	p := LogPattern{Name: strings.TrimSpace(name), Regex: regex}
+	if !ok || !pluginNamePattern.MatchString(p.Name) {
+		return LogPattern{}, fmt.Errorf("invalid log pattern %q: expected name=regex", value)
+	}
+	if _, err := regexp.Compile(p.Regex); err != nil {
+		return LogPattern{}, fmt.Errorf("invalid log pattern %q: %w", p.Name, err)
+	}
+	return p, nil
+}
+
+// read position of a followed file, saved between restarts
+type logOffset struct {
+	Inode  uint64 `json:"inode"`
+	Offset int64  `json:"offset"`
+}
+
+// followed file with its open handle
+type tailedFile struct {
+	path   string
+	file   *os.File
+	inode  uint64
+	offset int64
+	// position loaded from the state file, used when the file is first opened
+	saved *logOffset
+	// inside a line that was already counted when it exceeded maxLogLineBytes
+	skipping bool
+}
+
+type compiledPattern struct {
+	name string
+	re   *regexp.Regexp
+}
+
+// follows log files across rotation and counts lines matching patterns
+type LogTailer struct {
+	mu        sync.Mutex
+	files     []*tailedFile
+	patterns  []compiledPattern
+	sample    int
+	statePath string
+	// counts not confirmed by Commit yet, reported again with the next poll
+	pending map[string]*models.LogMetric
+}
+
+// create a tailer for files, sample is the number of matching lines forwarded per pattern and sample,
+// positions are kept in statePath when it is set
+func NewLogTailer(files []string, patterns []LogPattern, sample int, statePath string) (*LogTailer, error) {
+	if sample < 0 {
+		return nil, fmt.Errorf("number of forwarded log lines must not be negative")
+	}
+	t := &LogTailer{sample: sample, statePath: statePath, pending: make(map[string]*models.LogMetric)}
+	names := make(map[string]bool)
+	for _, p := range patterns {
+		if names[p.Name] {
+			return nil, fmt.Errorf("log pattern %q is configured twice", p.Name)
+		}
+		names[p.Name] = true
+		re, err := regexp.Compile(p.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid log pattern %q: %w", p.Name, err)
+		}
+		t.patterns = append(t.patterns, compiledPattern{name: p.Name, re: re})
+	}
+
+	saved, err := loadLogOffsets(statePath)
+	if err != nil {
+		return nil, err
+	}
+	paths := make(map[string]bool)
+	for _, path := range files {
+		if paths[path] {
+			return nil, fmt.Errorf("log file %s is configured twice", path)
+		}
+		paths[path] = true
+		f := &tailedFile{path: path}
+		if off, ok := saved[path]; ok {
+			f.saved = &off
+		}
+		t.files = append(t.files, f)
+	}
+	return t, nil
+}
+
+// read lines appended since the previous poll, counts of polls not confirmed by Commit are included
+func (t *LogTailer) Poll() ([]models.LogMetric, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var metrics []models.LogMetric
+	var errs []error
+	for _, f := range t.files {
+		m, pending := t.pending[f.path]
+		if !pending {
+			m = &models.LogMetric{Path: f.path, Matches: make(map[string]uint64)}
+		}
+		found, err := t.poll(f, m)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", f.path, err))
+		}
+		// lines read before an error are kept
+		if (!found || err != nil) && !pending && m.Lines == 0 {
+			continue
+		}
+		t.pending[f.path] = m
+		metrics = append(metrics, models.LogMetric{
+			Path:    m.Path,
+			Lines:   m.Lines,
+			Matches: maps.Clone(m.Matches),
+			Samples: slices.Clone(m.Samples),
+		})
+	}
+	return metrics, errors.Join(errs...)
+}
+
+// confirm the counts of previous polls as sent and save the read positions
+func (t *LogTailer) Commit() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	clear(t.pending)
+	return t.saveOffsets()
+}
+
+// close all followed files, positions are only saved by Commit
+// so lines of unconfirmed polls are read again after a restart
+func (t *LogTailer) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, f := range t.files {
+		if f.file != nil {
+			f.saved = &logOffset{Inode: f.inode, Offset: f.offset}
+			f.file.Close()
+			f.file = nil
+		}
+	}
+	return nil
+}
+
+// count new lines of one file into m, false when the file does not exist yet
+func (t *LogTailer) poll(f *tailedFile, m *models.LogMetric) (bool, error) {
+	info, err := os.Stat(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		// rotated away and not recreated yet, finish the old file
+		// a file created later is read from its start
+		f.saved = &logOffset{}
+		if f.file != nil {
+			return true, t.drain(f, m)
+		}
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	inode := fileInode(info)
+
+	switch {
+	case f.file == nil:
+		if err := t.open(f, inode, info.Size()); err != nil {
+			return false, err
+		}
+	case f.inode != inode:
+		// rotated by rename, read what was written to the old file before switching
+		if err := t.drain(f, m); err != nil {
+			return true, err
+		}
+		f.saved = &logOffset{}
+		if err := t.open(f, inode, info.Size()); err != nil {
+			return true, err
+		}
+	case info.Size() < f.offset:
+		// truncated in place, e.g. by copytruncate
+		f.offset = 0
+		f.skipping = false
+	}
+
+	return true, t.read(f, m, false)
+}
+
+// open a file at the saved position, at its end when it was never read
+func (t *LogTailer) open(f *tailedFile, inode uint64, size int64) error {
+	file, err := os.Open(f.path)
+	if err != nil {
+		return err
+	}
+	f.file = file
+	f.inode = inode
+	f.skipping = false
+
+	switch {
+	case f.saved == nil:
+		// do not count lines written before the agent started following the file
+		f.offset = size
+	case f.saved.Inode == inode && f.saved.Offset <= size:
+		f.offset = f.saved.Offset
+	default:
+		// rotated or truncated while the agent was stopped
+		f.offset = 0
+	}
+	f.saved = nil
+	return nil
+}
+
+// read the rest of a rotated file and close it
+func (t *LogTailer) drain(f *tailedFile, m *models.LogMetric) error {
+	err := t.read(f, m, true)
+	f.file.Close()
+	f.file = nil
+	return err
+}
+
+// count complete lines from the current offset, a trailing partial line is kept for the next read
+// unless the file is final, a final file is read to its end instead of maxLogReadBytes
+func (t *LogTailer) read(f *tailedFile, m *models.LogMetric, final bool) error {
+	info, err := f.file.Stat()
+	if err != nil {
+		return err
+	}
+	end := info.Size()
+	for f.offset < end {
+		data := make([]byte, min(end-f.offset, maxLogReadBytes))
+		n, err := f.file.ReadAt(data, f.offset)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		if n == 0 {
+			// shrunk while reading
+			return nil
+		}
+		t.scan(f, m, data[:n], final && f.offset+int64(n) >= end)
+		if !final {
+			return nil
+		}
+	}
+	return nil
+}
+
+// count the lines of data read at the current offset, last is set for the end of a final file
+func (t *LogTailer) scan(f *tailedFile, m *models.LogMetric, data []byte, last bool) {
+	for len(data) > 0 {
+		i := bytes.IndexByte(data, '\n')
+		if f.skipping {
+			// rest of an overlong line, it was counted with its first part
+			if i < 0 {
+				f.offset += int64(len(data))
+				return
+			}
+			data = data[i+1:]
+			f.offset += int64(i + 1)
+			f.skipping = false
+			continue
+		}
+		var line []byte
+		switch {
+		case i >= 0:
+			line, data = data[:i], data[i+1:]
+			f.offset += int64(i + 1)
+		case last:
+			line, data = data, nil
+			f.offset += int64(len(line))
+		case len(data) >= maxLogLineBytes:
+			line, data = data, nil
+			f.offset += int64(len(line))
+			f.skipping = true
+		default:
+			return
+		}
+		t.match(line, m)
+	}
+}
+
+// count a line and keep it as a sample when it matches a pattern
+func (t *LogTailer) match(line []byte, m *models.LogMetric) {
+	m.Lines++
+	line = bytes.TrimRight(line[:min(len(line), maxLogLineBytes)], "\r")
+	for _, p := range t.patterns {
+		if !p.re.Match(line) {
+			continue
+		}
+		m.Matches[p.name]++
+		if m.Matches[p.name] <= uint64(t.sample) {
+			m.Samples = append(m.Samples, models.LogSample{
+				Pattern: p.name,
+				Line:    strings.ToValidUTF8(string(line[:min(len(line), maxLogSampleLen)]), ""),
+			})
+		}
+	}
+}
+
+// load saved positions, missing state means no file was read yet
+func loadLogOffsets(path string) (map[string]logOffset, error) {
+	offsets := make(map[string]logOffset)
+	if path == "" {
+		return offsets, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return offsets, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read log state: %w", err)
+	}
+	if err := json.Unmarshal(data, &offsets); err != nil {
+		// a damaged state file only loses positions, do not stop the agent for it
+		log.Printf("Ignoring damaged log state %s: %v", path, err)
+		return make(map[string]logOffset), nil
+	}
+	return offsets, nil
+}
+
+// write positions of open files atomically, must be called with mutex held
+func (t *LogTailer) saveOffsets() error {
+	if t.statePath == "" {
+		return nil
+	}
+	offsets := make(map[string]logOffset, len(t.files))
+	for _, f := range t.files {
+		switch {
+		case f.file != nil:
+			offsets[f.path] = logOffset{Inode: f.inode, Offset: f.offset}
+		case f.saved != nil:
+			offsets[f.path] = *f.saved
+		}
+	}
+	data, err := json.Marshal(offsets)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(t.statePath), 0o750); err != nil {
+		return fmt.Errorf("create log state dir: %w", err)
+	}
+	tmp := t.statePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o640); err != nil {
+		return fmt.Errorf("write log state: %w", err)
+	}
+	if err := os.Rename(tmp, t.statePath); err != nil {
+		return fmt.Errorf("write log state: %w", err)
+	}
+	return nil
+}
+
+// inode number identifying a file across renames
+func fileInode(info os.FileInfo) uint64 {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		return uint64(st.Ino)
+	}
+	return 0
+}
+
+// reports log lines and pattern matches since the previous sample
+type logCollector struct {
+	tailer *LogTailer
+}
+
+func newLogCollector(cfg *Config) (*logCollector, error) {
+	tailer, err := NewLogTailer(cfg.LogFiles, cfg.LogPatterns, cfg.LogSampleLines, cfg.LogStateFile)
+	if err != nil {
+		return nil, err
+	}
+	return &logCollector{tailer: tailer}, nil
+}
+
+func (c *logCollector) Name() string { return "logs" }
+
+// close followed files
+func (c *logCollector) Close() error { return c.tailer.Close() }
+
+// confirm the reported counts once the sample is published
+func (c *logCollector) Commit() error { return c.tailer.Commit() }
+
+func (c *logCollector) Collect(context.Context) (Apply, error) {
+	if len(c.tailer.files) == 0 {
+		return nil, nil
+	}
+	logs, err := c.tailer.Poll()
+	if len(logs) == 0 {
+		return nil, err
+	}
+	// files that could be read are reported even when others failed
+	return func(m *models.Metric) { m.Logs = logs }, err
+}
@@ -0,0 +1,270 @@
+package agent_test
+
+import (
+	"data_agent/internal/agent"
+	"data_agent/internal/models"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testLogPatterns = []agent.LogPattern{
+	{Name: "errors", Regex: `(?i)error`},
+	{Name: "timeouts", Regex: `timeout`},
+}
+
+// append lines to a log file
+func appendLog(t *testing.T, path, text string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	require.NoError(t, err)
+	_, err = f.WriteString(text)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+}
+
+// poll a tailer that follows a single file and confirm the counts as sent
+func pollOne(t *testing.T, tailer *agent.LogTailer) models.LogMetric {
+	t.Helper()
+	logs, err := tailer.Poll()
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	require.NoError(t, tailer.Commit())
+	return logs[0]
+}
+
+// TestParseLogPattern verifies the name=regex flag syntax
+func TestParseLogPattern(t *testing.T) {
+	p, err := agent.ParseLogPattern("errors=level=(error|fatal)")
+	require.NoError(t, err)
+	assert.Equal(t, agent.LogPattern{Name: "errors", Regex: "level=(error|fatal)"}, p)
+
+	for _, value := range []string{"errors", "=error", "bad name=error", "errors=("} {
+		_, err := agent.ParseLogPattern(value)
+		assert.Error(t, err, value)
+	}
+}
+
+// TestLogTailerCountsNewLines verifies matching, sampling and partial lines
+func TestLogTailerCountsNewLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	appendLog(t, path, "ERROR written before the agent started\n")
+
+	tailer, err := agent.NewLogTailer([]string{path}, testLogPatterns, 1, "")
+	require.NoError(t, err)
+
+	// existing content is skipped
+	m := pollOne(t, tailer)
+	assert.Equal(t, path, m.Path)
+	assert.Zero(t, m.Lines)
+
+	appendLog(t, path, "started\nerror: disk full\nError: request timeout\npartial error")
+	m = pollOne(t, tailer)
+	assert.Equal(t, uint64(3), m.Lines)
+	assert.Equal(t, map[string]uint64{"errors": 2, "timeouts": 1}, m.Matches)
+	assert.Equal(t, []models.LogSample{
+		{Pattern: "errors", Line: "error: disk full"},
+		{Pattern: "timeouts", Line: "Error: request timeout"},
+	}, m.Samples)
+
+	// the partial line is counted once it is complete
+	appendLog(t, path, " line\n")
+	m = pollOne(t, tailer)
+	assert.Equal(t, uint64(1), m.Lines)
+	assert.Equal(t, uint64(1), m.Matches["errors"])
+	assert.Equal(t, "partial error line", m.Samples[0].Line)
+}
+
+// TestLogTailerRotation verifies that lines written before a rename are not lost
+func TestLogTailerRotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	appendLog(t, path, "")
+
+	tailer, err := agent.NewLogTailer([]string{path}, testLogPatterns, 0, "")
+	require.NoError(t, err)
+	pollOne(t, tailer)
+
+	appendLog(t, path, "error before rotation\n")
+	require.NoError(t, os.Rename(path, path+".1"))
+	appendLog(t, path+".1", "error after rename\n")
+
+	// rotated away and not recreated yet
+	m := pollOne(t, tailer)
+	assert.Equal(t, uint64(2), m.Lines)
+	assert.Equal(t, uint64(2), m.Matches["errors"])
+	assert.Empty(t, m.Samples)
+
+	// the new file is read from its start
+	appendLog(t, path, "error in new file\nok\n")
+	m = pollOne(t, tailer)
+	assert.Equal(t, uint64(2), m.Lines)
+	assert.Equal(t, uint64(1), m.Matches["errors"])
+
+	// rename and recreate between two polls
+	appendLog(t, path, "error one\n")
+	require.NoError(t, os.Rename(path, path+".2"))
+	appendLog(t, path, "error two\n")
+	m = pollOne(t, tailer)
+	assert.Equal(t, uint64(2), m.Lines)
+	assert.Equal(t, uint64(2), m.Matches["errors"])
+}
+
+// TestLogTailerTruncation verifies copytruncate style rotation
+func TestLogTailerTruncation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	appendLog(t, path, "")
+
+	tailer, err := agent.NewLogTailer([]string{path}, testLogPatterns, 0, "")
+	require.NoError(t, err)
+	pollOne(t, tailer)
+
+	appendLog(t, path, "a fairly long line that will be truncated\n")
+	assert.Equal(t, uint64(1), pollOne(t, tailer).Lines)
+
+	require.NoError(t, os.Truncate(path, 0))
+	appendLog(t, path, "error\n")
+	m := pollOne(t, tailer)
+	assert.Equal(t, uint64(1), m.Lines)
+	assert.Equal(t, uint64(1), m.Matches["errors"])
+}
+
+// TestLogTailerLongLines verifies that an overlong line is counted once however it is split
+func TestLogTailerLongLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	appendLog(t, path, "")
+
+	tailer, err := agent.NewLogTailer([]string{path}, testLogPatterns, 1, "")
+	require.NoError(t, err)
+	pollOne(t, tailer)
+
+	long := "error " + strings.Repeat("x", 100<<10)
+	appendLog(t, path, long[:70<<10])
+	m := pollOne(t, tailer)
+	assert.Equal(t, uint64(1), m.Lines)
+	assert.Equal(t, uint64(1), m.Matches["errors"])
+	require.Len(t, m.Samples, 1)
+	assert.Len(t, m.Samples[0].Line, 1024)
+
+	// the rest of the line is skipped, including what is written later
+	appendLog(t, path, long[70<<10:])
+	assert.Zero(t, pollOne(t, tailer).Lines)
+	appendLog(t, path, "\nerror too\nok\n")
+	m = pollOne(t, tailer)
+	assert.Equal(t, uint64(2), m.Lines)
+	assert.Equal(t, uint64(1), m.Matches["errors"])
+}
+
+// TestLogTailerDrainLargeFile verifies that a rotated file is read to its end
+func TestLogTailerDrainLargeFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	appendLog(t, path, "")
+
+	tailer, err := agent.NewLogTailer([]string{path}, testLogPatterns, 0, "")
+	require.NoError(t, err)
+	pollOne(t, tailer)
+
+	// more than is read from a followed file per sample
+	line := strings.Repeat("x", 1023) + "\n"
+	appendLog(t, path, strings.Repeat(line, 9<<10)+"error at the end")
+	require.NoError(t, os.Rename(path, path+".1"))
+
+	m := pollOne(t, tailer)
+	assert.Equal(t, uint64(9<<10+1), m.Lines)
+	assert.Equal(t, uint64(1), m.Matches["errors"])
+}
+
+// TestLogTailerState verifies that positions survive a restart
+func TestLogTailerState(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	missing := filepath.Join(dir, "later.log")
+	state := filepath.Join(dir, "state", "logtail.json")
+	appendLog(t, path, "old\n")
+
+	tailer, err := agent.NewLogTailer([]string{path, missing}, testLogPatterns, 0, state)
+	require.NoError(t, err)
+	logs, err := tailer.Poll()
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	require.NoError(t, tailer.Commit())
+	require.NoError(t, tailer.Close())
+
+	// lines written while the agent was stopped are read after the restart
+	appendLog(t, path, "error while stopped\n")
+	appendLog(t, missing, "error in a new file\n")
+
+	tailer, err = agent.NewLogTailer([]string{path, missing}, testLogPatterns, 0, state)
+	require.NoError(t, err)
+	logs, err = tailer.Poll()
+	require.NoError(t, err)
+	require.Len(t, logs, 2)
+	for _, m := range logs {
+		assert.Equal(t, uint64(1), m.Lines, m.Path)
+		assert.Equal(t, uint64(1), m.Matches["errors"], m.Path)
+	}
+
+	// a file replaced while the agent was stopped is read from its start
+	require.NoError(t, tailer.Commit())
+	require.NoError(t, tailer.Close())
+	require.NoError(t, os.Remove(path))
+	appendLog(t, path, "first\nsecond\n")
+
+	tailer, err = agent.NewLogTailer([]string{path}, testLogPatterns, 0, state)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), pollOne(t, tailer).Lines)
+}
+
+// TestLogTailerUncommitted verifies that counts of an unsent sample are not lost
+func TestLogTailerUncommitted(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	state := filepath.Join(dir, "logtail.json")
+	appendLog(t, path, "")
+
+	tailer, err := agent.NewLogTailer([]string{path}, testLogPatterns, 2, state)
+	require.NoError(t, err)
+	pollOne(t, tailer)
+
+	appendLog(t, path, "error one\n")
+	logs, err := tailer.Poll()
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.Equal(t, uint64(1), logs[0].Lines)
+
+	// the sample was dropped, its counts are reported again with the next one
+	appendLog(t, path, "error two\nerror three\n")
+	m := pollOne(t, tailer)
+	assert.Equal(t, uint64(3), m.Lines)
+	assert.Equal(t, uint64(3), m.Matches["errors"])
+	assert.Equal(t, []models.LogSample{
+		{Pattern: "errors", Line: "error one"},
+		{Pattern: "errors", Line: "error two"},
+	}, m.Samples)
+	assert.Zero(t, pollOne(t, tailer).Lines)
+
+	// a restart before the sample was sent reads its lines again
+	appendLog(t, path, "error four\n")
+	logs, err = tailer.Poll()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), logs[0].Lines)
+	require.NoError(t, tailer.Close())
+
+	tailer, err = agent.NewLogTailer([]string{path}, testLogPatterns, 0, state)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), pollOne(t, tailer).Lines)
+}
+
+// TestNewLogTailerValidation verifies rejected settings
+func TestNewLogTailerValidation(t *testing.T) {
+	_, err := agent.NewLogTailer([]string{"/var/log/a.log", "/var/log/a.log"}, nil, 0, "")
+	assert.Error(t, err)
+	_, err = agent.NewLogTailer(nil, append(testLogPatterns, testLogPatterns[0]), 0, "")
+	assert.Error(t, err)
+	_, err = agent.NewLogTailer(nil, testLogPatterns, -1, "")
+	assert.Error(t, err)
+}
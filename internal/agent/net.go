@@ -0,0 +1,55 @@
+package agent
+
+import (
+	"context"
+	"data_agent/internal/models"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/host"
+)
+
+// collects network counters per interface with rates since the previous sample
+type netCollector struct {
+	mu       sync.Mutex
+	prev     map[string]models.NetMetric
+	prevTime time.Time
+	bootTime uint64
+}
+
+func (c *netCollector) Name() string { return "network" }
+
+func (c *netCollector) Collect(ctx context.Context) (Apply, error) {
+	netMetric, err := CollectNetMetric()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	bootTime, err := host.BootTimeWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// counters restart from zero after a reboot
+	if bootTime != c.bootTime {
+		c.prev = nil
+	}
+	elapsed := now.Sub(c.prevTime)
+	for i, cur := range netMetric {
+		if prev, ok := c.prev[cur.Name]; ok {
+			netMetric[i].Rates = NetMetricRates(prev, cur, elapsed)
+		}
+	}
+
+	c.prev = make(map[string]models.NetMetric, len(netMetric))
+	for _, m := range netMetric {
+		c.prev[m.Name] = m
+	}
+	c.prevTime = now
+	c.bootTime = bootTime
+
+	return func(m *models.Metric) { m.Network = netMetric }, nil
+}
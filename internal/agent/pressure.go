@@ -0,0 +1,127 @@
+package agent
+
+import (
+	"bufio"
+	"context"
+	"data_agent/internal/models"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+const defaultProcRoot = "/proc"
+
+// collects Pressure Stall Information from /proc/pressure
+type pressureCollector struct {
+	procRoot string
+	once     sync.Once
+}
+
+func (c *pressureCollector) Name() string { return "pressure" }
+
+func (c *pressureCollector) Collect(context.Context) (Apply, error) {
+	pressure, err := ReadPressure(c.procRoot)
+	if err != nil {
+		return nil, err
+	}
+	if pressure == nil {
+		c.once.Do(func() { log.Printf("PSI is not available under %s, pressure is not reported", c.procRoot) })
+		return nil, nil
+	}
+	return func(m *models.Metric) { m.Pressure = pressure }, nil
+}
+
+// read cpu, memory and io pressure, nil when the kernel has no PSI support
+func ReadPressure(procRoot string) (*models.PressureMetric, error) {
+	var pressure models.PressureMetric
+	found := false
+	for _, res := range []struct {
+		name string
+		stat **models.PressureStat
+	}{
+		{"cpu", &pressure.CPU},
+		{"memory", &pressure.Memory},
+		{"io", &pressure.IO},
+	} {
+		f, err := os.Open(filepath.Join(procRoot, "pressure", res.name))
+		// kernels built without PSI or booted with psi=0
+		if errors.Is(err, os.ErrNotExist) || errors.Is(err, syscall.EOPNOTSUPP) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		stat, err := ParsePressure(f)
+		f.Close()
+		if errors.Is(err, syscall.EOPNOTSUPP) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse %s pressure: %w", res.name, err)
+		}
+		*res.stat = &stat
+		found = true
+	}
+
+	if !found {
+		return nil, nil
+	}
+	return &pressure, nil
+}
+
+// parse the some and full lines of a pressure file
+func ParsePressure(r io.Reader) (models.PressureStat, error) {
+	var stat models.PressureStat
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		var line models.PressureLine
+		for _, field := range fields[1:] {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				return stat, fmt.Errorf("invalid field %q", field)
+			}
+			var err error
+			switch key {
+			case "avg10":
+				line.Avg10, err = strconv.ParseFloat(value, 64)
+			case "avg60":
+				line.Avg60, err = strconv.ParseFloat(value, 64)
+			case "avg300":
+				line.Avg300, err = strconv.ParseFloat(value, 64)
+			case "total":
+				line.Total, err = strconv.ParseUint(value, 10, 64)
+			}
+			if err != nil {
+				return stat, fmt.Errorf("invalid value of %s: %w", key, err)
+			}
+		}
+
+		switch fields[0] {
+		case "some":
+			stat.Some = &line
+		case "full":
+			stat.Full = &line
+		default:
+			return stat, fmt.Errorf("unknown pressure line %q", fields[0])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return stat, err
+	}
+	if stat.Some == nil {
+		return stat, errors.New("missing some line")
+	}
+	return stat, nil
+}
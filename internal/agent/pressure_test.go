@@ -0,0 +1,66 @@
+package agent_test
+
+import (
+	"data_agent/internal/agent"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReadPressure verifies parsing of the fixture pressure files
+func TestReadPressure(t *testing.T) {
+	pressure, err := agent.ReadPressure("testdata/proc")
+	require.NoError(t, err)
+	require.NotNil(t, pressure)
+
+	require.NotNil(t, pressure.CPU)
+	assert.InDelta(t, 1.53, pressure.CPU.Some.Avg10, 0.001)
+	assert.Equal(t, uint64(123456789), pressure.CPU.Some.Total)
+
+	require.NotNil(t, pressure.Memory)
+	require.NotNil(t, pressure.Memory.Full)
+	assert.InDelta(t, 4.75, pressure.Memory.Full.Avg10, 0.001)
+	assert.InDelta(t, 2.00, pressure.Memory.Full.Avg60, 0.001)
+	assert.InDelta(t, 0.60, pressure.Memory.Full.Avg300, 0.001)
+
+	require.NotNil(t, pressure.IO)
+	assert.Equal(t, uint64(4440000), pressure.IO.Full.Total)
+}
+
+// TestReadPressureUnavailable ensures a kernel without PSI yields no data and no error
+func TestReadPressureUnavailable(t *testing.T) {
+	pressure, err := agent.ReadPressure(t.TempDir())
+
+	assert.NoError(t, err)
+	assert.Nil(t, pressure)
+}
+
+// TestReadPressurePartial checks that missing resources are skipped
+func TestReadPressurePartial(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "pressure"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "pressure", "io"),
+		[]byte("some avg10=0.50 avg60=0.40 avg300=0.30 total=100\n"), 0o644))
+
+	pressure, err := agent.ReadPressure(root)
+
+	require.NoError(t, err)
+	require.NotNil(t, pressure)
+	assert.Nil(t, pressure.CPU)
+	assert.Nil(t, pressure.Memory)
+	require.NotNil(t, pressure.IO)
+	assert.Nil(t, pressure.IO.Full)
+}
+
+// TestParsePressureInvalid verifies malformed input is rejected
+func TestParsePressureInvalid(t *testing.T) {
+	_, err := agent.ParsePressure(strings.NewReader("some avg10=abc\n"))
+	assert.Error(t, err)
+
+	_, err = agent.ParsePressure(strings.NewReader("full avg10=1.00 avg60=1.00 avg300=1.00 total=1\n"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,295 @@
+package agent
+
+import (
+	"context"
+	"data_agent/internal/models"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultProbeTimeout = 3 * time.Second
+	// response bodies beyond this size are not matched
+	maxProbeBody = 1 << 20
+)
+
+// client of HTTP probes, a probe checks the endpoint itself so redirects are reported
+// instead of followed and neither proxies from the environment nor idle connections are used
+var probeClient = &http.Client{
+	CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	Transport:     &http.Transport{DisableKeepAlives: true},
+}
+
+// kinds of probes
+const (
+	ProbeHTTP = "http"
+	ProbeTCP  = "tcp"
+	ProbeDNS  = "dns"
+)
+
+// synthetic check of an endpoint run with every sample
+type Probe struct {
+	Name   string
+	Type   string
+	Target string
+	// expected HTTP status, zero accepts any status below 400
+	ExpectStatus int
+	// regular expression the HTTP response body must match
+	ExpectBody string
+	// DNS server as host:port, empty uses the system resolver
+	DNSServer string
+	Timeout   time.Duration
+}
+
+// validation of probe settings
+func (p *Probe) Validate() error {
+	if !pluginNamePattern.MatchString(p.Name) {
+		return fmt.Errorf("invalid probe name %q: expected letters, digits, '_', '.' or '-'", p.Name)
+	}
+	if p.Timeout <= 0 {
+		return fmt.Errorf("probe %q needs a positive timeout", p.Name)
+	}
+	switch p.Type {
+	case ProbeHTTP:
+		u, err := url.Parse(p.Target)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("probe %q needs an http or https URL", p.Name)
+		}
+		if _, err := regexp.Compile(p.ExpectBody); err != nil {
+			return fmt.Errorf("probe %q has an invalid body pattern: %w", p.Name, err)
+		}
+	case ProbeTCP:
+		if _, _, err := net.SplitHostPort(p.Target); err != nil {
+			return fmt.Errorf("probe %q needs a host:port target: %w", p.Name, err)
+		}
+	case ProbeDNS:
+		if p.Target == "" {
+			return fmt.Errorf("probe %q needs a name to resolve", p.Name)
+		}
+		if p.DNSServer != "" {
+			if _, _, err := net.SplitHostPort(p.DNSServer); err != nil {
+				return fmt.Errorf("probe %q needs a host:port DNS server: %w", p.Name, err)
+			}
+		}
+	default:
+		return fmt.Errorf("probe %q has unknown type %q, expected %s, %s or %s", p.Name, p.Type, ProbeHTTP, ProbeTCP, ProbeDNS)
+	}
+	return nil
+}
+
+// parse a probe given as name=type:target with ;key=value options
+func ParseProbe(value string, timeout time.Duration) (Probe, error) {
+	p, err := splitProbe(value)
+	if err != nil {
+		return Probe{}, err
+	}
+	if p.Timeout == 0 {
+		p.Timeout = timeout
+	}
+	if err := p.Validate(); err != nil {
+		return Probe{}, err
+	}
+	return p, nil
+}
+
+// split name=type:target;key=value without validating the probe, timeout is zero unless given as option
+func splitProbe(value string) (Probe, error) {
+	name, rest, ok := strings.Cut(value, "=")
+	kind, rest, ok2 := strings.Cut(rest, ":")
+	if !ok || !ok2 {
+		return Probe{}, fmt.Errorf("invalid probe %q: expected name=type:target", value)
+	}
+	options := strings.Split(rest, ";")
+	p := Probe{
+		Name:   strings.TrimSpace(name),
+		Type:   strings.TrimSpace(kind),
+		Target: strings.TrimSpace(options[0]),
+	}
+
+	for _, option := range options[1:] {
+		key, raw, ok := strings.Cut(option, "=")
+		if !ok {
+			return Probe{}, fmt.Errorf("invalid option %q of probe %q: expected key=value", option, p.Name)
+		}
+		var err error
+		switch strings.TrimSpace(key) {
+		case "status":
+			p.ExpectStatus, err = strconv.Atoi(raw)
+		case "body":
+			p.ExpectBody = raw
+		case "server":
+			p.DNSServer = raw
+		case "timeout":
+			p.Timeout, err = time.ParseDuration(raw)
+			if err == nil && p.Timeout <= 0 {
+				err = errors.New("expected a positive duration")
+			}
+		default:
+			err = errors.New("unknown option")
+		}
+		if err != nil {
+			return Probe{}, fmt.Errorf("invalid option %q of probe %q: %w", option, p.Name, err)
+		}
+	}
+	return p, nil
+}
+
+// runs all probes concurrently for every sample
+type probeCollector struct {
+	probes []Probe
+	// compiled ExpectBody of each probe, nil when the body is not checked
+	bodies []*regexp.Regexp
+}
+
+func newProbeCollector(cfg *Config) (*probeCollector, error) {
+	names := make(map[string]bool)
+	for _, p := range cfg.Probes {
+		if err := p.Validate(); err != nil {
+			return nil, err
+		}
+		if names[p.Name] {
+			return nil, fmt.Errorf("probe %q is configured twice", p.Name)
+		}
+		names[p.Name] = true
+	}
+	c := &probeCollector{probes: cfg.Probes, bodies: make([]*regexp.Regexp, len(cfg.Probes))}
+	for i, p := range cfg.Probes {
+		if p.ExpectBody != "" {
+			c.bodies[i] = regexp.MustCompile(p.ExpectBody)
+		}
+	}
+	return c, nil
+}
+
+func (c *probeCollector) Name() string { return "probes" }
+
+func (c *probeCollector) Collect(ctx context.Context) (Apply, error) {
+	if len(c.probes) == 0 {
+		return nil, nil
+	}
+
+	results := make([]models.ProbeResult, len(c.probes))
+	var wg sync.WaitGroup
+	for i, p := range c.probes {
+		wg.Add(1)
+		go func(i int, p Probe) {
+			defer wg.Done()
+			results[i] = runProbe(ctx, p, c.bodies[i])
+		}(i, p)
+	}
+	wg.Wait()
+
+	return func(m *models.Metric) { m.Probes = results }, nil
+}
+
+// run a single probe, failures are reported in the result
+func RunProbe(ctx context.Context, p Probe) models.ProbeResult {
+	var body *regexp.Regexp
+	if p.ExpectBody != "" {
+		// validated when the probe was configured
+		body = regexp.MustCompile(p.ExpectBody)
+	}
+	return runProbe(ctx, p, body)
+}
+
+// run a probe with its compiled body pattern
+func runProbe(ctx context.Context, p Probe, body *regexp.Regexp) models.ProbeResult {
+	ctx, cancel := context.WithTimeout(ctx, p.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	result := models.ProbeResult{Name: p.Name, Type: p.Type, Target: p.Target, Time: start}
+
+	var err error
+	switch p.Type {
+	case ProbeHTTP:
+		result.StatusCode, err = probeHTTP(ctx, p, body)
+	case ProbeTCP:
+		err = probeTCP(ctx, p)
+	case ProbeDNS:
+		err = probeDNS(ctx, p)
+	default:
+		err = fmt.Errorf("unknown probe type %q", p.Type)
+	}
+
+	result.LatencyMs = float64(time.Since(start).Microseconds()) / 1000
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Success = true
+	return result
+}
+
+// fetch a URL and check its status and body, a nil pattern accepts any body
+func probeHTTP(ctx context.Context, p Probe, pattern *regexp.Regexp) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.Target, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", "data-agent-probe")
+
+	resp, err := probeClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxProbeBody))
+	if err != nil {
+		return resp.StatusCode, fmt.Errorf("read body: %w", err)
+	}
+
+	switch {
+	case p.ExpectStatus != 0 && resp.StatusCode != p.ExpectStatus:
+		return resp.StatusCode, fmt.Errorf("unexpected status %d, expected %d", resp.StatusCode, p.ExpectStatus)
+	case p.ExpectStatus == 0 && resp.StatusCode >= 400:
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	if pattern != nil && !pattern.Match(body) {
+		return resp.StatusCode, fmt.Errorf("body does not match %q", p.ExpectBody)
+	}
+	return resp.StatusCode, nil
+}
+
+// open and close a TCP connection
+func probeTCP(ctx context.Context, p Probe) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", p.Target)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// resolve a name with the system resolver or a given server
+func probeDNS(ctx context.Context, p Probe) error {
+	resolver := net.DefaultResolver
+	if p.DNSServer != "" {
+		resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, p.DNSServer)
+			},
+		}
+	}
+
+	addrs, err := resolver.LookupHost(ctx, p.Target)
+	if err != nil {
+		return err
+	}
+	if len(addrs) == 0 {
+		return fmt.Errorf("no addresses for %s", p.Target)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package agent_test
+
+import (
+	"context"
+	"data_agent/internal/agent"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseProbe verifies the name=type:target flag syntax with options
+func TestParseProbe(t *testing.T) {
+	p, err := agent.ParseProbe("api=http:https://example.com/health;status=200;body=\"ok\";timeout=2s", time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "api", p.Name)
+	assert.Equal(t, agent.ProbeHTTP, p.Type)
+	assert.Equal(t, "https://example.com/health", p.Target)
+	assert.Equal(t, 200, p.ExpectStatus)
+	assert.Equal(t, `"ok"`, p.ExpectBody)
+	assert.Equal(t, 2*time.Second, p.Timeout)
+
+	p, err = agent.ParseProbe("resolver=dns:example.com;server=1.1.1.1:53", time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "1.1.1.1:53", p.DNSServer)
+
+	for _, value := range []string{
+		"api", "api=http", "api=ftp:ftp://example.com", "api=http:example.com",
+		"db=tcp:localhost", "api=http:http://x;status=abc", "api=http:http://x;retries=3",
+		"api=http:http://x;body=(", "dns=dns:example.com;server=1.1.1.1",
+	} {
+		_, err := agent.ParseProbe(value, time.Second)
+		assert.Error(t, err, value)
+	}
+}
+
+// TestRunProbeHTTP checks status and body expectations against a local server
+func TestRunProbeHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			http.NotFound(w, r)
+			return
+		case "/moved":
+			http.Redirect(w, r, "/missing", http.StatusMovedPermanently)
+			return
+		}
+		fmt.Fprint(w, `{"status": "ok"}`)
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	result := agent.RunProbe(ctx, agent.Probe{Name: "ok", Type: agent.ProbeHTTP, Target: server.URL, ExpectBody: `"status": "ok"`, Timeout: time.Second})
+	assert.True(t, result.Success, result.Error)
+	assert.Equal(t, http.StatusOK, result.StatusCode)
+	assert.Positive(t, result.LatencyMs)
+
+	result = agent.RunProbe(ctx, agent.Probe{Name: "body", Type: agent.ProbeHTTP, Target: server.URL, ExpectBody: "degraded", Timeout: time.Second})
+	assert.False(t, result.Success)
+	assert.Contains(t, result.Error, "does not match")
+
+	result = agent.RunProbe(ctx, agent.Probe{Name: "missing", Type: agent.ProbeHTTP, Target: server.URL + "/missing", Timeout: time.Second})
+	assert.False(t, result.Success)
+	assert.Equal(t, http.StatusNotFound, result.StatusCode)
+
+	result = agent.RunProbe(ctx, agent.Probe{Name: "expected", Type: agent.ProbeHTTP, Target: server.URL + "/missing", ExpectStatus: http.StatusNotFound, Timeout: time.Second})
+	assert.True(t, result.Success, result.Error)
+
+	// redirects are reported, not followed
+	result = agent.RunProbe(ctx, agent.Probe{Name: "moved", Type: agent.ProbeHTTP, Target: server.URL + "/moved", ExpectStatus: http.StatusMovedPermanently, Timeout: time.Second})
+	assert.True(t, result.Success, result.Error)
+	assert.Equal(t, http.StatusMovedPermanently, result.StatusCode)
+	result = agent.RunProbe(ctx, agent.Probe{Name: "moved", Type: agent.ProbeHTTP, Target: server.URL + "/moved", ExpectStatus: http.StatusOK, Timeout: time.Second})
+	assert.False(t, result.Success)
+	assert.Equal(t, http.StatusMovedPermanently, result.StatusCode)
+}
+
+// TestRunProbeTCP checks connecting to an open and a closed port
+func TestRunProbeTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+
+	result := agent.RunProbe(context.Background(), agent.Probe{Name: "open", Type: agent.ProbeTCP, Target: addr, Timeout: time.Second})
+	assert.True(t, result.Success, result.Error)
+
+	require.NoError(t, ln.Close())
+	result = agent.RunProbe(context.Background(), agent.Probe{Name: "closed", Type: agent.ProbeTCP, Target: addr, Timeout: time.Second})
+	assert.False(t, result.Success)
+	assert.NotEmpty(t, result.Error)
+}
+
+// TestRunProbeDNS checks resolving a name known to every host
+func TestRunProbeDNS(t *testing.T) {
+	result := agent.RunProbe(context.Background(), agent.Probe{Name: "local", Type: agent.ProbeDNS, Target: "localhost", Timeout: time.Second})
+	assert.True(t, result.Success, result.Error)
+}
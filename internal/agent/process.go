@@ -0,0 +1,183 @@
+package agent
+
+import (
+	"context"
+	"data_agent/internal/models"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+const (
+	defaultTopProcesses = 10
+	defaultCmdlineMax   = 256
+	redactedValue       = "***"
+)
+
+// secrets passed as command line arguments, the first group is replaced
+var defaultRedactPatterns = []string{
+	`(?i)(?:password|passwd|pwd|secret|token|api[-_]?key|access[-_]?key)[=:\s]+(\S+)`,
+	`(?i)://[^:/@\s]+:([^@/\s]+)@`,
+}
+
+// masks secrets in command lines and limits their length
+type CmdlineRedactor struct {
+	patterns []*regexp.Regexp
+	max      int
+}
+
+// compile the default patterns followed by extra ones
+func NewCmdlineRedactor(extra []string, max int) (*CmdlineRedactor, error) {
+	if max <= 0 {
+		max = defaultCmdlineMax
+	}
+	r := &CmdlineRedactor{max: max}
+	for _, pattern := range append(append([]string(nil), defaultRedactPatterns...), extra...) {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redact pattern %q: %w", pattern, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+// replace secrets and truncate the command line
+func (r *CmdlineRedactor) Redact(cmdline string) string {
+	for _, re := range r.patterns {
+		cmdline = redactMatches(re, cmdline)
+	}
+	if runes := []rune(cmdline); len(runes) > r.max {
+		cmdline = string(runes[:r.max]) + "..."
+	}
+	return cmdline
+}
+
+// replace the first group of every match, or the whole match without groups
+func redactMatches(re *regexp.Regexp, s string) string {
+	matches := re.FindAllStringSubmatchIndex(s, -1)
+	if matches == nil {
+		return s
+	}
+
+	var out []byte
+	last := 0
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		if len(m) >= 4 && m[2] >= 0 {
+			start, end = m[2], m[3]
+		}
+		out = append(out, s[last:start]...)
+		out = append(out, redactedValue...)
+		last = end
+	}
+	return string(append(out, s[last:]...))
+}
+
+// identity of a process, a reused pid belongs to a process with another creation time
+type processKey struct {
+	pid     int32
+	created int64
+}
+
+// collects the processes using the most CPU and memory
+type processCollector struct {
+	mu       sync.Mutex
+	top      int
+	redactor *CmdlineRedactor
+	// kept between samples so CPU usage is measured over the interval
+	procs map[processKey]*process.Process
+}
+
+// create a process collector from settings
+func newProcessCollector(cfg *Config) (*processCollector, error) {
+	top := cfg.TopProcesses
+	if top <= 0 {
+		top = defaultTopProcesses
+	}
+	redactor, err := NewCmdlineRedactor(cfg.ProcessRedact, cfg.ProcessCmdlineMax)
+	if err != nil {
+		return nil, err
+	}
+	return &processCollector{top: top, redactor: redactor, procs: make(map[processKey]*process.Process)}, nil
+}
+
+func (c *processCollector) Name() string { return "processes" }
+
+func (c *processCollector) Collect(ctx context.Context) (Apply, error) {
+	pids, err := process.PidsWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	type usage struct {
+		proc *process.Process
+		cpu  float64
+		rss  uint64
+	}
+	alive := make(map[processKey]*process.Process, len(pids))
+	usages := make([]usage, 0, len(pids))
+	for _, pid := range pids {
+		// the creation time is read anew, a cached process keeps the one of its first sample
+		fresh, err := process.NewProcessWithContext(ctx, pid)
+		if err != nil {
+			// the process exited meanwhile
+			continue
+		}
+		created, err := fresh.CreateTimeWithContext(ctx)
+		if err != nil {
+			continue
+		}
+		key := processKey{pid: pid, created: created}
+		p, ok := c.procs[key]
+		if !ok {
+			p = fresh
+		}
+		alive[key] = p
+
+		cpuPercent, err := p.PercentWithContext(ctx, 0)
+		if err != nil {
+			continue
+		}
+		memInfo, err := p.MemoryInfoWithContext(ctx)
+		if err != nil || memInfo == nil {
+			continue
+		}
+		usages = append(usages, usage{proc: p, cpu: cpuPercent, rss: memInfo.RSS})
+	}
+	c.procs = alive
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var table models.ProcessTable
+	sort.SliceStable(usages, func(i, j int) bool { return usages[i].cpu > usages[j].cpu })
+	for _, u := range usages[:min(c.top, len(usages))] {
+		table.ByCPU = append(table.ByCPU, c.describe(ctx, u.proc, u.cpu, u.rss))
+	}
+	sort.SliceStable(usages, func(i, j int) bool { return usages[i].rss > usages[j].rss })
+	for _, u := range usages[:min(c.top, len(usages))] {
+		table.ByRSS = append(table.ByRSS, c.describe(ctx, u.proc, u.cpu, u.rss))
+	}
+
+	return func(m *models.Metric) { m.Processes = &table }, nil
+}
+
+// fill in details of a selected process, fields that cannot be read stay empty
+func (c *processCollector) describe(ctx context.Context, p *process.Process, cpuPercent float64, rss uint64) models.ProcessMetric {
+	pm := models.ProcessMetric{PID: p.Pid, CPUPercent: cpuPercent, RSS: rss}
+	pm.Name, _ = p.NameWithContext(ctx)
+	pm.User, _ = p.UsernameWithContext(ctx)
+	pm.Threads, _ = p.NumThreadsWithContext(ctx)
+	pm.OpenFDs, _ = p.NumFDsWithContext(ctx)
+	if cmdline, err := p.CmdlineWithContext(ctx); err == nil {
+		pm.Cmdline = c.redactor.Redact(cmdline)
+	}
+	return pm
+}
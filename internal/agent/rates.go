@@ -0,0 +1,115 @@
+package agent
+
+import (
+	"data_agent/internal/models"
+	"math"
+	"time"
+)
+
+// growth of a cumulative counter, false when the counter was reset
+func counterDelta(prev, cur uint64) (uint64, bool) {
+	if cur < prev {
+		// the counter restarted, e.g. the device was recreated
+		return 0, false
+	}
+	return cur - prev, true
+}
+
+// growth of a counter the kernel keeps in 32 bits, which wraps around instead of being reset
+func counter32Delta(prev, cur uint64) (uint64, bool) {
+	if cur < prev && prev <= math.MaxUint32 && prev > math.MaxUint32/2 {
+		return math.MaxUint32 - prev + cur + 1, true
+	}
+	return counterDelta(prev, cur)
+}
+
+// per-second rate of a cumulative counter, false when the counter was reset
+func CounterRate(prev, cur uint64, elapsed time.Duration) (float64, bool) {
+	if elapsed <= 0 {
+		return 0, false
+	}
+	delta, ok := counterDelta(prev, cur)
+	if !ok {
+		return 0, false
+	}
+	return float64(delta) / elapsed.Seconds(), true
+}
+
+// network rates between two samples of the same interface, nil when any counter was reset
+func NetMetricRates(prev, cur models.NetMetric, elapsed time.Duration) *models.NetRates {
+	var rates models.NetRates
+	pairs := []struct {
+		prev, cur uint64
+		rate      *float64
+	}{
+		{prev.BytesSent, cur.BytesSent, &rates.BytesSentPerSec},
+		{prev.BytesRecv, cur.BytesRecv, &rates.BytesRecvPerSec},
+		{prev.PacketsSent, cur.PacketsSent, &rates.PacketsSentPerSec},
+		{prev.PacketsRecv, cur.PacketsRecv, &rates.PacketsRecvPerSec},
+		{prev.ErrIn, cur.ErrIn, &rates.ErrInPerSec},
+		{prev.ErrOut, cur.ErrOut, &rates.ErrOutPerSec},
+		{prev.DropIn, cur.DropIn, &rates.DropInPerSec},
+		{prev.DropOut, cur.DropOut, &rates.DropOutPerSec},
+	}
+	for _, p := range pairs {
+		rate, ok := CounterRate(p.prev, p.cur, elapsed)
+		if !ok {
+			return nil
+		}
+		*p.rate = rate
+	}
+	return &rates
+}
+
+// block device rates between two samples of the same device, nil when any counter was reset
+func DiskIOMetricRates(prev, cur models.DiskIOMetric, elapsed time.Duration) *models.DiskIORates {
+	if elapsed <= 0 {
+		return nil
+	}
+
+	var reads, writes, readBytes, writeBytes, readTime, writeTime, ioTime, weighted uint64
+	// diskstats prints the times as 32-bit milliseconds
+	pairs := []struct {
+		prev, cur uint64
+		delta     *uint64
+		wraps     bool
+	}{
+		{prev.ReadCount, cur.ReadCount, &reads, false},
+		{prev.WriteCount, cur.WriteCount, &writes, false},
+		{prev.ReadBytes, cur.ReadBytes, &readBytes, false},
+		{prev.WriteBytes, cur.WriteBytes, &writeBytes, false},
+		{prev.ReadTimeMs, cur.ReadTimeMs, &readTime, true},
+		{prev.WriteTimeMs, cur.WriteTimeMs, &writeTime, true},
+		{prev.IOTimeMs, cur.IOTimeMs, &ioTime, true},
+		{prev.WeightedIOMs, cur.WeightedIOMs, &weighted, true},
+	}
+	for _, p := range pairs {
+		delta, ok := counterDelta(p.prev, p.cur)
+		if p.wraps {
+			delta, ok = counter32Delta(p.prev, p.cur)
+		}
+		if !ok {
+			return nil
+		}
+		*p.delta = delta
+	}
+
+	seconds := elapsed.Seconds()
+	elapsedMs := seconds * 1000
+	rates := &models.DiskIORates{
+		ReadOpsPerSec:    float64(reads) / seconds,
+		WriteOpsPerSec:   float64(writes) / seconds,
+		ReadBytesPerSec:  float64(readBytes) / seconds,
+		WriteBytesPerSec: float64(writeBytes) / seconds,
+		UtilPercent:      clampPercent(float64(ioTime) / elapsedMs * 100),
+		AvgQueueSize:     float64(weighted) / elapsedMs,
+	}
+	// average time per completed request
+	if reads > 0 {
+		rates.AvgReadLatency = float64(readTime) / float64(reads)
+	}
+	if writes > 0 {
+		rates.AvgWriteLatency = float64(writeTime) / float64(writes)
+	}
+	return rates
+}
@@ -0,0 +1,102 @@
+package agent_test
+
+import (
+	"data_agent/internal/agent"
+	"data_agent/internal/models"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCounterRate verifies the per-second rate of a growing counter
+func TestCounterRate(t *testing.T) {
+	rate, ok := agent.CounterRate(1000, 3000, 2*time.Second)
+
+	assert.True(t, ok)
+	assert.InDelta(t, 1000.0, rate, 0.001)
+}
+
+
+// TestCounterRateReset ensures a restarted counter is reported as a reset instead of a bogus rate
+func TestCounterRateReset(t *testing.T) {
+	_, ok := agent.CounterRate(10_000_000_000, 500, time.Second)
+	assert.False(t, ok)
+
+	_, ok = agent.CounterRate(100, 50, time.Second)
+	assert.False(t, ok)
+
+	// 64-bit counters do not wrap at 32 bits
+	_, ok = agent.CounterRate(math.MaxUint32-99, 100, time.Second)
+	assert.False(t, ok)
+
+	_, ok = agent.CounterRate(100, 200, 0)
+	assert.False(t, ok)
+}
+
+// TestNetMetricRates verifies rates for every network counter
+func TestNetMetricRates(t *testing.T) {
+	prev := models.NetMetric{Name: "eth0", BytesSent: 100, BytesRecv: 200, PacketsSent: 1, PacketsRecv: 2}
+	cur := models.NetMetric{Name: "eth0", BytesSent: 300, BytesRecv: 600, PacketsSent: 3, PacketsRecv: 6, ErrIn: 2, DropOut: 4}
+
+	rates := agent.NetMetricRates(prev, cur, 2*time.Second)
+
+	require.NotNil(t, rates)
+	assert.InDelta(t, 100.0, rates.BytesSentPerSec, 0.001)
+	assert.InDelta(t, 200.0, rates.BytesRecvPerSec, 0.001)
+	assert.InDelta(t, 1.0, rates.PacketsSentPerSec, 0.001)
+	assert.InDelta(t, 2.0, rates.PacketsRecvPerSec, 0.001)
+	assert.InDelta(t, 1.0, rates.ErrInPerSec, 0.001)
+	assert.InDelta(t, 2.0, rates.DropOutPerSec, 0.001)
+}
+
+// TestNetMetricRatesReset ensures an interface with a reset counter has no rates
+func TestNetMetricRatesReset(t *testing.T) {
+	prev := models.NetMetric{Name: "eth0", BytesSent: 10_000_000_000}
+	cur := models.NetMetric{Name: "eth0", BytesSent: 10}
+
+	assert.Nil(t, agent.NetMetricRates(prev, cur, time.Second))
+}
+
+// TestDiskIOMetricRates verifies throughput, utilization, latency and queue size of a block device
+func TestDiskIOMetricRates(t *testing.T) {
+	prev := models.DiskIOMetric{Device: "sda", ReadCount: 100, WriteCount: 50, ReadBytes: 4096, WriteBytes: 8192}
+	cur := models.DiskIOMetric{
+		Device:       "sda",
+		ReadCount:    300,
+		WriteCount:   150,
+		ReadBytes:    4096 + 2*1024*1024,
+		WriteBytes:   8192 + 4*1024*1024,
+		ReadTimeMs:   400,
+		WriteTimeMs:  500,
+		IOTimeMs:     1000,
+		WeightedIOMs: 3000,
+	}
+
+	rates := agent.DiskIOMetricRates(prev, cur, 2*time.Second)
+
+	require.NotNil(t, rates)
+	assert.InDelta(t, 100.0, rates.ReadOpsPerSec, 0.001)
+	assert.InDelta(t, 50.0, rates.WriteOpsPerSec, 0.001)
+	assert.InDelta(t, 1024*1024.0, rates.ReadBytesPerSec, 0.001)
+	assert.InDelta(t, 2*1024*1024.0, rates.WriteBytesPerSec, 0.001)
+	assert.InDelta(t, 50.0, rates.UtilPercent, 0.001)
+	assert.InDelta(t, 2.0, rates.AvgReadLatency, 0.001)
+	assert.InDelta(t, 5.0, rates.AvgWriteLatency, 0.001)
+	assert.InDelta(t, 1.5, rates.AvgQueueSize, 0.001)
+}
+
+// TestDiskIOMetricRatesWrap32 checks that the 32-bit time counters of diskstats wrap while the others reset
+func TestDiskIOMetricRatesWrap32(t *testing.T) {
+	prev := models.DiskIOMetric{Device: "sda", ReadCount: 100, IOTimeMs: math.MaxUint32 - 499}
+	cur := models.DiskIOMetric{Device: "sda", ReadCount: 300, IOTimeMs: 500}
+
+	rates := agent.DiskIOMetricRates(prev, cur, 2*time.Second)
+	require.NotNil(t, rates)
+	assert.InDelta(t, 50.0, rates.UtilPercent, 0.001)
+
+	prev.ReadCount = math.MaxUint32 - 99
+	assert.Nil(t, agent.DiskIOMetricRates(prev, cur, 2*time.Second))
+}
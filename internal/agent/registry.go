@@ -0,0 +1,348 @@
+package agent
+
+import (
+	"context"
+	"data_agent/internal/models"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// default time limit for a single collector
+const defaultCollectorTimeout = 5 * time.Second
+
+// gathers one group of metrics for a sample
+type Collector interface {
+	// unique name used in configuration and error reports
+	Name() string
+	// gather metrics and return a function that writes them into the sample,
+	// an error returned together with a function reports values that were left out
+	Collect(ctx context.Context) (Apply, error)
+}
+
+// writes collected values into a metric sample
+type Apply func(m *models.Metric)
+
+// collector that keeps reporting its values until they are confirmed as sent,
+// Commit is called after a sample holding them was published
+type Committer interface {
+	Commit() error
+}
+
+// constructor of a collector from agent settings
+type CollectorFactory func(cfg *Config) (Collector, error)
+
+type factoryEntry struct {
+	name    string
+	factory CollectorFactory
+}
+
+var (
+	factoriesMu sync.Mutex
+	factories   []factoryEntry
+)
+
+// make a collector available to every registry built from config
+func RegisterCollector(name string, factory CollectorFactory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+
+	for _, f := range factories {
+		if f.name == name {
+			panic("agent: collector registered twice: " + name)
+		}
+	}
+	factories = append(factories, factoryEntry{name: name, factory: factory})
+}
+
+// names of all registered collector factories
+func CollectorNames() []string {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+
+	names := make([]string, 0, len(factories))
+	for _, f := range factories {
+		names = append(names, f.name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+type registryEntry struct {
+	collector Collector
+	enabled   bool
+	timeout   time.Duration
+}
+
+// set of collectors run for every sample
+type Registry struct {
+	mu      sync.RWMutex
+	entries []*registryEntry
+	timeout time.Duration
+	// collectors whose values went into the last sample
+	uncommitted []Collector
+}
+
+// create an empty registry with a default collector timeout
+func NewRegistry(timeout time.Duration) *Registry {
+	if timeout <= 0 {
+		timeout = defaultCollectorTimeout
+	}
+	return &Registry{timeout: timeout}
+}
+
+// build a registry with all registered collectors configured from settings
+func NewRegistryFromConfig(cfg *Config) (*Registry, error) {
+	return buildRegistry(cfg, nil)
+}
+
+// build a registry from settings, reuse returns a running collector to keep instead of creating one,
+// or nil, collectors created here are closed again when the registry cannot be built
+func buildRegistry(cfg *Config, reuse func(name string) Collector) (*Registry, error) {
+	factoriesMu.Lock()
+	registered := append([]factoryEntry(nil), factories...)
+	factoriesMu.Unlock()
+
+	var created []Collector
+	fail := func(err error) (*Registry, error) {
+		if cerr := closeCollectors(created); cerr != nil {
+			log.Println("Failed to close collectors:", cerr)
+		}
+		return nil, err
+	}
+
+	r := NewRegistry(cfg.CollectorTimeout)
+	for _, f := range registered {
+		var c Collector
+		if reuse != nil {
+			c = reuse(f.name)
+		}
+		if c == nil {
+			var err error
+			if c, err = f.factory(cfg); err != nil {
+				return fail(fmt.Errorf("create collector %q: %w", f.name, err))
+			}
+			created = append(created, c)
+		}
+		if c.Name() != f.name {
+			return fail(fmt.Errorf("collector %q reports name %q", f.name, c.Name()))
+		}
+		if err := r.Register(c); err != nil {
+			return fail(err)
+		}
+	}
+
+	for _, name := range cfg.DisabledCollectors {
+		if err := r.SetEnabled(name, false); err != nil {
+			return fail(err)
+		}
+	}
+	for name, timeout := range cfg.CollectorTimeouts {
+		if err := r.SetTimeout(name, timeout); err != nil {
+			return fail(err)
+		}
+	}
+	return r, nil
+}
+
+// add an enabled collector with the default timeout
+func (r *Registry) Register(c Collector) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, e := range r.entries {
+		if e.collector.Name() == c.Name() {
+			return fmt.Errorf("collector %q is already registered", c.Name())
+		}
+	}
+	r.entries = append(r.entries, &registryEntry{collector: c, enabled: true, timeout: r.timeout})
+	return nil
+}
+
+// enable or disable a collector by name
+func (r *Registry) SetEnabled(name string, enabled bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, err := r.find(name)
+	if err != nil {
+		return err
+	}
+	e.enabled = enabled
+	return nil
+}
+
+// change the time limit of a collector by name
+func (r *Registry) SetTimeout(name string, timeout time.Duration) error {
+	if timeout <= 0 {
+		return fmt.Errorf("timeout of collector %q must be positive", name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, err := r.find(name)
+	if err != nil {
+		return err
+	}
+	e.timeout = timeout
+	return nil
+}
+
+// release collectors that hold resources such as open files, the registry is not used afterwards
+func (r *Registry) Close() error {
+	return r.closeExcept(nil)
+}
+
+// release collectors except those named in keep, which were taken over by another registry,
+// the registry is not used afterwards
+func (r *Registry) closeExcept(keep map[string]bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var collectors []Collector
+	for _, e := range r.entries {
+		if !keep[e.collector.Name()] {
+			collectors = append(collectors, e.collector)
+		}
+	}
+	return closeCollectors(collectors)
+}
+
+// running collector by name, nil when there is none
+func (r *Registry) collector(name string) Collector {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	e, err := r.find(name)
+	if err != nil {
+		return nil
+	}
+	return e.collector
+}
+
+// close collectors that hold resources
+func closeCollectors(collectors []Collector) error {
+	var errs []error
+	for _, c := range collectors {
+		if closer, ok := c.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("close collector %q: %w", c.Name(), err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// names of enabled collectors in registration order
+func (r *Registry) Enabled() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var names []string
+	for _, e := range r.entries {
+		if e.enabled {
+			names = append(names, e.collector.Name())
+		}
+	}
+	return names
+}
+
+// run enabled collectors concurrently and build a sample from those that succeed
+func (r *Registry) Collect(ctx context.Context) *models.Metric {
+	r.mu.RLock()
+	var entries []registryEntry
+	for _, e := range r.entries {
+		if e.enabled {
+			entries = append(entries, *e)
+		}
+	}
+	r.mu.RUnlock()
+
+	type result struct {
+		apply Apply
+		err   error
+	}
+
+	start := time.Now()
+	metric := &models.Metric{Time: start}
+	var applied []Collector
+	results := make([]chan result, len(entries))
+	for i, e := range entries {
+		// buffered so a collector that outlives its timeout does not leak blocked
+		results[i] = make(chan result, 1)
+		go func(e registryEntry, out chan<- result) {
+			cctx, cancel := context.WithTimeout(ctx, e.timeout)
+			defer cancel()
+			apply, err := e.collector.Collect(cctx)
+			out <- result{apply: apply, err: err}
+		}(e, results[i])
+	}
+
+	// apply results in registration order so later collectors win on overlap
+	for i, e := range entries {
+		timer := time.NewTimer(time.Until(start.Add(e.timeout)))
+		var res result
+		select {
+		case res = <-results[i]:
+		case <-timer.C:
+			res.err = fmt.Errorf("timed out after %s", e.timeout)
+		case <-ctx.Done():
+			res.err = ctx.Err()
+		}
+		timer.Stop()
+
+		if res.err != nil {
+			metric.Errors = append(metric.Errors, models.CollectorError{
+				Collector: e.collector.Name(),
+				Error:     res.err.Error(),
+			})
+		}
+		if res.apply != nil {
+			res.apply(metric)
+			if _, ok := e.collector.(Committer); ok {
+				applied = append(applied, e.collector)
+			}
+		}
+	}
+
+	r.mu.Lock()
+	r.uncommitted = applied
+	r.mu.Unlock()
+	return metric
+}
+
+// confirm the values of the last sample as published
+func (r *Registry) Commit() error {
+	r.mu.Lock()
+	applied := r.uncommitted
+	r.uncommitted = nil
+	r.mu.Unlock()
+
+	var errs []error
+	for _, c := range applied {
+		if err := c.(Committer).Commit(); err != nil {
+			errs = append(errs, fmt.Errorf("commit collector %q: %w", c.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// look up an entry by name, must be called with mutex held
+func (r *Registry) find(name string) (*registryEntry, error) {
+	for _, e := range r.entries {
+		if e.collector.Name() == name {
+			return e, nil
+		}
+	}
+	known := make([]string, 0, len(r.entries))
+	for _, e := range r.entries {
+		known = append(known, e.collector.Name())
+	}
+	return nil, fmt.Errorf("unknown collector %q, known collectors: %s", name, strings.Join(known, ", "))
+}
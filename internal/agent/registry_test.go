@@ -0,0 +1,167 @@
+package agent_test
+
+import (
+	"context"
+	"data_agent/internal/agent"
+	"data_agent/internal/models"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCollector returns a fixed result after an optional delay
+type fakeCollector struct {
+	name  string
+	delay time.Duration
+	err   error
+	apply agent.Apply
+}
+
+func (f fakeCollector) Name() string { return f.name }
+
+func (f fakeCollector) Collect(ctx context.Context) (agent.Apply, error) {
+	select {
+	case <-time.After(f.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return f.apply, f.err
+}
+
+// TestRegistryPartialFailure ensures a failing collector is reported while the others still apply
+func TestRegistryPartialFailure(t *testing.T) {
+	r := agent.NewRegistry(time.Second)
+	require.NoError(t, r.Register(fakeCollector{name: "ram", apply: func(m *models.Metric) { m.RAM = 42 }}))
+	require.NoError(t, r.Register(fakeCollector{name: "network", err: errors.New("no counters")}))
+
+	metric := r.Collect(context.Background())
+
+	assert.Equal(t, 42.0, metric.RAM)
+	assert.Equal(t, []models.CollectorError{{Collector: "network", Error: "no counters"}}, metric.Errors)
+}
+
+// TestRegistryPartialResult checks that values returned together with an error are still applied
+func TestRegistryPartialResult(t *testing.T) {
+	r := agent.NewRegistry(time.Second)
+	require.NoError(t, r.Register(fakeCollector{
+		name:  "disk",
+		apply: func(m *models.Metric) { m.Disk = []models.DiskMetric{{Path: "/", UsedPercent: 40}} },
+		err:   errors.New("invalid disk used percent for /data"),
+	}))
+
+	metric := r.Collect(context.Background())
+
+	assert.Equal(t, []models.DiskMetric{{Path: "/", UsedPercent: 40}}, metric.Disk)
+	assert.Equal(t, []models.CollectorError{{Collector: "disk", Error: "invalid disk used percent for /data"}}, metric.Errors)
+	assert.NoError(t, metric.Validate())
+}
+
+// TestRegistryTimeout verifies a slow collector is reported as timed out without blocking the sample
+func TestRegistryTimeout(t *testing.T) {
+	r := agent.NewRegistry(time.Second)
+	require.NoError(t, r.Register(fakeCollector{name: "slow", delay: time.Minute}))
+	require.NoError(t, r.Register(fakeCollector{name: "ram", apply: func(m *models.Metric) { m.RAM = 10 }}))
+	require.NoError(t, r.SetTimeout("slow", 50*time.Millisecond))
+
+	start := time.Now()
+	metric := r.Collect(context.Background())
+
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, 10.0, metric.RAM)
+	require.Len(t, metric.Errors, 1)
+	assert.Equal(t, "slow", metric.Errors[0].Collector)
+}
+
+// TestRegistryDisabledCollector checks that disabled collectors are skipped
+func TestRegistryDisabledCollector(t *testing.T) {
+	r := agent.NewRegistry(time.Second)
+	require.NoError(t, r.Register(fakeCollector{name: "ram", apply: func(m *models.Metric) { m.RAM = 42 }}))
+	require.NoError(t, r.SetEnabled("ram", false))
+
+	metric := r.Collect(context.Background())
+
+	assert.Zero(t, metric.RAM)
+	assert.Empty(t, metric.Errors)
+	assert.Empty(t, r.Enabled())
+}
+
+// TestRegistryRejectsDuplicatesAndUnknownNames verifies name validation of the registry
+func TestRegistryRejectsDuplicatesAndUnknownNames(t *testing.T) {
+	r := agent.NewRegistry(time.Second)
+	require.NoError(t, r.Register(fakeCollector{name: "cpu"}))
+
+	assert.Error(t, r.Register(fakeCollector{name: "cpu"}))
+	assert.Error(t, r.SetEnabled("gpu", false))
+	assert.Error(t, r.SetTimeout("cpu", 0))
+}
+
+// TestNewRegistryFromConfig checks that built-in collectors are registered and configured
+func TestNewRegistryFromConfig(t *testing.T) {
+	r, err := agent.NewRegistryFromConfig(&agent.Config{DisabledCollectors: []string{"disk"}})
+	require.NoError(t, err)
+
+	enabled := r.Enabled()
+	assert.Contains(t, enabled, "cpu")
+	assert.NotContains(t, enabled, "disk")
+}
+
+// closingCollector records whether it was closed
+type closingCollector struct {
+	fakeCollector
+	closed *bool
+}
+
+func (c closingCollector) Close() error {
+	*c.closed = true
+	return errors.New("already closed")
+}
+
+// TestRegistryClose ensures collectors holding resources are closed and their errors reported
+func TestRegistryClose(t *testing.T) {
+	var closed bool
+	r := agent.NewRegistry(time.Second)
+	require.NoError(t, r.Register(fakeCollector{name: "cpu"}))
+	require.NoError(t, r.Register(closingCollector{fakeCollector: fakeCollector{name: "logs"}, closed: &closed}))
+
+	err := r.Close()
+	assert.True(t, closed)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"logs"`)
+}
+
+// committingCollector counts confirmed samples
+type committingCollector struct {
+	fakeCollector
+	commits *int
+}
+
+func (c committingCollector) Commit() error {
+	*c.commits++
+	return nil
+}
+
+// TestRegistryCommit ensures only collectors whose values went into the sample are confirmed
+func TestRegistryCommit(t *testing.T) {
+	var applied, failed int
+	r := agent.NewRegistry(time.Second)
+	require.NoError(t, r.Register(committingCollector{
+		fakeCollector: fakeCollector{name: "logs", apply: func(m *models.Metric) {}},
+		commits:       &applied,
+	}))
+	require.NoError(t, r.Register(committingCollector{
+		fakeCollector: fakeCollector{name: "slow", delay: time.Minute},
+		commits:       &failed,
+	}))
+	require.NoError(t, r.SetTimeout("slow", 50*time.Millisecond))
+
+	r.Collect(context.Background())
+	require.NoError(t, r.Commit())
+	// a second commit without a new sample confirms nothing
+	require.NoError(t, r.Commit())
+
+	assert.Equal(t, 1, applied)
+	assert.Zero(t, failed)
+}
@@ -0,0 +1,184 @@
+package agent
+
+import (
+	"context"
+	"data_agent/internal/queue"
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// publisher with its spool, replaced when broker or spool settings change
+type output struct {
+	publisher *queue.Publisher
+	spool     *queue.Spool
+	cancel    context.CancelFunc
+	done      chan struct{}
+}
+
+// open the spool that keeps metrics on disk while RabbitMQ is unreachable, nil when it is disabled
+func openSpool(cfg *Config) (*queue.Spool, error) {
+	if cfg.SpoolDir == "" {
+		return nil, nil
+	}
+	return queue.OpenSpool(queue.SpoolConfig{
+		Dir:           cfg.SpoolDir,
+		MaxTotalBytes: cfg.SpoolMaxBytes,
+		MaxAge:        cfg.SpoolMaxAge,
+	})
+}
+
+// create and start a publisher writing to spool, nil runs it without one
+func startOutput(ctx context.Context, cfg *Config, spool *queue.Spool) *output {
+	ctx, cancel := context.WithCancel(ctx)
+	publisher := queue.NewPublisher(ctx, cfg.RabbitURL)
+	publisher.Window = cfg.PublishWindow
+	publisher.Spool = spool
+
+	// publisher spools unconfirmed metrics on stop, so wait for it
+	done := make(chan struct{})
+	go func() {
+		publisher.StartMetricsPublisher()
+		close(done)
+	}()
+	return &output{publisher: publisher, spool: spool, cancel: cancel, done: done}
+}
+
+// stop the publisher and wait until it spooled unconfirmed metrics
+func (o *output) stop() {
+	o.cancel()
+	<-o.done
+}
+
+// components of a running agent that are replaced on reload
+type runner struct {
+	cfg      *Config
+	registry *Registry
+	output   *output
+	ticker   *time.Ticker
+}
+
+// switch to a new configuration, only components whose settings changed are rebuilt,
+// the running ones are kept when the new collectors or the new spool cannot be set up
+func (r *runner) reload(ctx context.Context, cfg *Config) error {
+	if _, err := hostMachineID(cfg); err != nil {
+		return err
+	}
+
+	// collectors with unchanged settings move to the new registry with their state
+	registry := r.registry
+	var kept map[string]bool
+	if !sameCollectors(r.cfg, cfg) {
+		kept = make(map[string]bool)
+		var err error
+		registry, err = buildRegistry(cfg, func(name string) Collector {
+			if !sameCollectorSettings(name, r.cfg, cfg) {
+				return nil
+			}
+			c := r.registry.collector(name)
+			kept[name] = c != nil
+			return c
+		})
+		if err != nil {
+			return fmt.Errorf("configure collectors: %w", err)
+		}
+	}
+
+	if !samePublisher(r.cfg, cfg) {
+		spool, err := r.nextSpool(cfg)
+		if err != nil {
+			if registry != r.registry {
+				if cerr := registry.closeExcept(kept); cerr != nil {
+					log.Println("Failed to close collectors:", cerr)
+				}
+			}
+			return fmt.Errorf("open spool: %w", err)
+		}
+
+		// the old publisher spools its unconfirmed metrics before the new one starts
+		r.output.stop()
+		logPublisherStats(r.output.publisher)
+		if old := r.output.spool; old != nil && old != spool {
+			if err := old.Close(); err != nil {
+				log.Println("Failed to close spool:", err)
+			}
+		}
+		r.output = startOutput(ctx, cfg, spool)
+		log.Println("Publisher restarted for", maskURL(cfg.RabbitURL))
+	}
+
+	if registry != r.registry {
+		if err := r.registry.closeExcept(kept); err != nil {
+			log.Println("Failed to close collectors:", err)
+		}
+		r.registry = registry
+		log.Println("Enabled collectors:", strings.Join(registry.Enabled(), ", "))
+	}
+	if cfg.Interval != r.cfg.Interval {
+		r.ticker.Reset(cfg.Interval)
+		log.Println("Collection interval changed to", cfg.Interval)
+	}
+	r.cfg = cfg
+	log.Println("Configuration reloaded")
+	return nil
+}
+
+// spool for the new configuration, the running one is kept when only its limits change
+// because a second spool in the same directory would miss what the old publisher spools on stop
+func (r *runner) nextSpool(cfg *Config) (*queue.Spool, error) {
+	spool := r.output.spool
+	switch {
+	case sameSpool(r.cfg, cfg):
+		return spool, nil
+	case spool != nil && cfg.SpoolDir == r.cfg.SpoolDir:
+		spool.SetLimits(cfg.SpoolMaxBytes, cfg.SpoolMaxAge)
+		return spool, nil
+	default:
+		return openSpool(cfg)
+	}
+}
+
+// release collectors and the spool once the publisher stopped
+func (r *runner) close() {
+	if err := r.registry.Close(); err != nil {
+		log.Println("Failed to close collectors:", err)
+	}
+	if r.output.spool != nil {
+		if err := r.output.spool.Close(); err != nil {
+			log.Println("Failed to close spool:", err)
+		}
+	}
+}
+
+// publisher settings are equal and the connection can be kept
+func samePublisher(a, b *Config) bool {
+	return a.RabbitURL == b.RabbitURL && a.PublishWindow == b.PublishWindow && sameSpool(a, b)
+}
+
+// spool settings are equal and the open spool can be kept
+func sameSpool(a, b *Config) bool {
+	return a.SpoolDir == b.SpoolDir && a.SpoolMaxBytes == b.SpoolMaxBytes && a.SpoolMaxAge == b.SpoolMaxAge
+}
+
+// collector settings are equal and collectors keep their state such as counters of the previous sample
+func sameCollectors(a, b *Config) bool {
+	return reflect.DeepEqual(collectorSettingsOf(a), collectorSettingsOf(b))
+}
+
+// settings a single collector is created from are equal and the running collector can be kept
+func sameCollectorSettings(name string, a, b *Config) bool {
+	inputs, ok := collectorInputs[name]
+	return ok && reflect.DeepEqual(inputs(a), inputs(b))
+}
+
+// copy of a configuration without settings that collectors do not use
+func collectorSettingsOf(cfg *Config) Config {
+	c := *cfg
+	c.RabbitURL, c.Interval, c.PublishWindow = "", 0, 0
+	c.SpoolDir, c.SpoolMaxBytes, c.SpoolMaxAge = "", 0, 0
+	c.MachineID, c.Labels = "", nil
+	c.ConfigFile, c.PrintConfig, c.settings = "", false, nil
+	return c
+}
@@ -0,0 +1,173 @@
+package agent
+
+import (
+	"context"
+	"data_agent/internal/models"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// collects temperatures and fan speeds from hwmon and thermal zones,
+// read directly from sysfs instead of host.SensorsTemperatures so fans and a custom root are supported
+type sensorCollector struct {
+	sysRoot string
+	once    sync.Once
+}
+
+func (c *sensorCollector) Name() string { return "sensors" }
+
+func (c *sensorCollector) Collect(context.Context) (Apply, error) {
+	sensors, err := ReadSensors(c.sysRoot)
+	if err != nil {
+		return nil, err
+	}
+	if sensors == nil {
+		c.once.Do(func() { log.Printf("No hardware sensors found under %s, sensors are not reported", c.sysRoot) })
+		return nil, nil
+	}
+	return func(m *models.Metric) { m.Sensors = sensors }, nil
+}
+
+// read hwmon and thermal zone sensors, nil when the host exposes none
+func ReadSensors(sysRoot string) (*models.SensorMetric, error) {
+	var sensors models.SensorMetric
+	if err := readHwmon(filepath.Join(sysRoot, "class", "hwmon"), &sensors); err != nil {
+		return nil, err
+	}
+	if err := readThermalZones(filepath.Join(sysRoot, "class", "thermal"), &sensors); err != nil {
+		return nil, err
+	}
+	if len(sensors.Temperatures) == 0 && len(sensors.Fans) == 0 {
+		return nil, nil
+	}
+
+	sort.Slice(sensors.Temperatures, func(i, j int) bool { return sensors.Temperatures[i].Name < sensors.Temperatures[j].Name })
+	sort.Slice(sensors.Fans, func(i, j int) bool { return sensors.Fans[i].Name < sensors.Fans[j].Name })
+	return &sensors, nil
+}
+
+// read temp*_input and fan*_input of every hwmon chip
+func readHwmon(dir string, sensors *models.SensorMetric) error {
+	chips, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, chip := range chips {
+		chipDir := filepath.Join(dir, chip.Name())
+		chipName := readSysString(filepath.Join(chipDir, "name"))
+		if chipName == "" {
+			chipName = chip.Name()
+		}
+		// chips of the same driver, e.g. coretemp of every socket, are told apart by their device
+		device := chip.Name()
+		if link, err := os.Readlink(filepath.Join(chipDir, "device")); err == nil {
+			device = filepath.Base(link)
+		}
+
+		inputs, err := filepath.Glob(filepath.Join(chipDir, "*_input"))
+		if err != nil {
+			return err
+		}
+		for _, input := range inputs {
+			prefix := strings.TrimSuffix(filepath.Base(input), "_input")
+			// sensors that are not connected fail to read and are skipped
+			value, ok := readSysInt(input)
+			if !ok {
+				continue
+			}
+			label := readSysString(filepath.Join(chipDir, prefix+"_label"))
+			if label == "" {
+				label = prefix
+			}
+			name := chipName + "/" + device + "/" + label
+
+			switch {
+			case strings.HasPrefix(prefix, "temp"):
+				temp := models.TemperatureSensor{Name: name, Current: milliCelsius(value)}
+				if high, ok := readSysInt(filepath.Join(chipDir, prefix+"_max")); ok {
+					temp.High = milliCelsius(high)
+				}
+				if crit, ok := readSysInt(filepath.Join(chipDir, prefix+"_crit")); ok {
+					temp.Critical = milliCelsius(crit)
+				}
+				sensors.Temperatures = append(sensors.Temperatures, temp)
+			case strings.HasPrefix(prefix, "fan"):
+				fan := models.FanSensor{Name: name, RPM: value}
+				if low, ok := readSysInt(filepath.Join(chipDir, prefix+"_min")); ok {
+					fan.Min = low
+				}
+				sensors.Fans = append(sensors.Fans, fan)
+			}
+		}
+	}
+	return nil
+}
+
+// read the temperature and hot and critical trip points of every thermal zone
+func readThermalZones(dir string, sensors *models.SensorMetric) error {
+	zones, err := filepath.Glob(filepath.Join(dir, "thermal_zone*"))
+	if err != nil {
+		return err
+	}
+
+	for _, zone := range zones {
+		value, ok := readSysInt(filepath.Join(zone, "temp"))
+		if !ok {
+			continue
+		}
+		name := "thermal/" + filepath.Base(zone)
+		if zoneType := readSysString(filepath.Join(zone, "type")); zoneType != "" {
+			name += "/" + zoneType
+		}
+		temp := models.TemperatureSensor{Name: name, Current: milliCelsius(value)}
+
+		trips, err := filepath.Glob(filepath.Join(zone, "trip_point_*_type"))
+		if err != nil {
+			return err
+		}
+		for _, trip := range trips {
+			tripTemp, ok := readSysInt(strings.TrimSuffix(trip, "_type") + "_temp")
+			if !ok {
+				continue
+			}
+			switch readSysString(trip) {
+			case "hot":
+				temp.High = milliCelsius(tripTemp)
+			case "critical":
+				temp.Critical = milliCelsius(tripTemp)
+			}
+		}
+		sensors.Temperatures = append(sensors.Temperatures, temp)
+	}
+	return nil
+}
+
+// sysfs reports temperatures in thousandths of a degree
+func milliCelsius(value int64) float64 {
+	return float64(value) / 1000
+}
+
+// read a trimmed sysfs attribute, empty when it cannot be read
+func readSysString(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// read an integer sysfs attribute
+func readSysInt(path string) (int64, bool) {
+	value, err := strconv.ParseInt(readSysString(path), 10, 64)
+	return value, err == nil
+}
@@ -0,0 +1,59 @@
+package agent_test
+
+import (
+	"data_agent/internal/agent"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReadSensors verifies hwmon and thermal zone fixtures are parsed into temperatures and fans
+func TestReadSensors(t *testing.T) {
+	sensors, err := agent.ReadSensors("testdata/sys")
+	require.NoError(t, err)
+	require.NotNil(t, sensors)
+
+	require.Len(t, sensors.Temperatures, 5, "unreadable inputs should be skipped")
+	pkg := sensors.Temperatures[0]
+	assert.Equal(t, "coretemp/coretemp.0/Core 0", pkg.Name)
+	assert.InDelta(t, 43.0, pkg.Current, 0.001)
+
+	pkg = sensors.Temperatures[1]
+	assert.Equal(t, "coretemp/coretemp.0/Package id 0", pkg.Name)
+	assert.InDelta(t, 45.0, pkg.Current, 0.001)
+	assert.InDelta(t, 80.0, pkg.High, 0.001)
+	assert.InDelta(t, 100.0, pkg.Critical, 0.001)
+
+	// the same core of the second socket
+	second := sensors.Temperatures[2]
+	assert.Equal(t, "coretemp/coretemp.1/Core 0", second.Name)
+	assert.InDelta(t, 51.0, second.Current, 0.001)
+
+	// chips without a device link are named after their hwmon directory
+	board := sensors.Temperatures[3]
+	assert.Equal(t, "nct6775/hwmon1/temp1", board.Name)
+	assert.InDelta(t, 38.5, board.Current, 0.001)
+	assert.Zero(t, board.High)
+
+	zone := sensors.Temperatures[4]
+	assert.Equal(t, "thermal/thermal_zone0/x86_pkg_temp", zone.Name)
+	assert.InDelta(t, 46.0, zone.Current, 0.001)
+	assert.Zero(t, zone.High, "passive trip points are not a high threshold")
+	assert.InDelta(t, 105.0, zone.Critical, 0.001)
+
+	require.Len(t, sensors.Fans, 2)
+	assert.Equal(t, "nct6775/hwmon1/CPU_FAN", sensors.Fans[0].Name)
+	assert.Equal(t, int64(1200), sensors.Fans[0].RPM)
+	assert.Equal(t, int64(300), sensors.Fans[0].Min)
+	assert.Equal(t, "nct6775/hwmon1/fan2", sensors.Fans[1].Name)
+	assert.Zero(t, sensors.Fans[1].RPM)
+}
+
+// TestReadSensorsNone ensures a host without sensors yields no data and no error
+func TestReadSensorsNone(t *testing.T) {
+	sensors, err := agent.ReadSensors(t.TempDir())
+
+	assert.NoError(t, err)
+	assert.Nil(t, sensors)
+}
@@ -0,0 +1,180 @@
+package agent
+
+import (
+	"bufio"
+	"context"
+	"data_agent/internal/models"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/shirou/gopsutil/v3/common"
+	"github.com/shirou/gopsutil/v3/net"
+)
+
+// collects TCP connection states and socket error counters with their growth since the previous sample
+type socketCollector struct {
+	mu       sync.Mutex
+	procRoot string
+	prev     *models.SocketCounters
+}
+
+func (c *socketCollector) Name() string { return "sockets" }
+
+func (c *socketCollector) Collect(ctx context.Context) (Apply, error) {
+	states, err := CountTCPStates(ctx, c.procRoot)
+	if err != nil {
+		return nil, err
+	}
+	counters, err := ReadSocketCounters(c.procRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sockets := &models.SocketMetric{TCPStates: states, Counters: counters}
+	if c.prev != nil {
+		sockets.Delta = SocketCountersDelta(*c.prev, counters)
+	}
+	c.prev = &counters
+
+	return func(m *models.Metric) { m.Sockets = sockets }, nil
+}
+
+// number of IPv4 and IPv6 TCP sockets per state
+func CountTCPStates(ctx context.Context, procRoot string) (map[string]int, error) {
+	ctx = context.WithValue(ctx, common.EnvKey, common.EnvMap{common.HostProcEnvKey: procRoot})
+	conns, err := net.ConnectionsWithoutUidsWithContext(ctx, "tcp")
+	if err != nil {
+		return nil, fmt.Errorf("list tcp connections: %w", err)
+	}
+
+	states := make(map[string]int)
+	for _, conn := range conns {
+		states[conn.Status]++
+	}
+	return states, nil
+}
+
+// read TCP and UDP counters from net/snmp and net/netstat
+func ReadSocketCounters(procRoot string) (models.SocketCounters, error) {
+	var counters models.SocketCounters
+
+	stats := make(map[string]map[string]int64)
+	for _, name := range []string{"snmp", "netstat"} {
+		f, err := os.Open(filepath.Join(procRoot, "net", name))
+		if err != nil {
+			return counters, err
+		}
+		parsed, err := ParseProtoStats(f)
+		f.Close()
+		if err != nil {
+			return counters, fmt.Errorf("parse %s: %w", name, err)
+		}
+		for proto, values := range parsed {
+			stats[proto] = values
+		}
+	}
+
+	for _, c := range []struct {
+		proto, name string
+		value       *uint64
+	}{
+		{"Tcp", "ActiveOpens", &counters.TCPActiveOpens},
+		{"Tcp", "PassiveOpens", &counters.TCPPassiveOpens},
+		{"Tcp", "AttemptFails", &counters.TCPAttemptFails},
+		{"Tcp", "EstabResets", &counters.TCPEstabResets},
+		{"Tcp", "OutSegs", &counters.TCPOutSegs},
+		{"Tcp", "RetransSegs", &counters.TCPRetransSegs},
+		{"Tcp", "InErrs", &counters.TCPInErrs},
+		{"Tcp", "OutRsts", &counters.TCPOutRsts},
+		{"TcpExt", "ListenOverflows", &counters.TCPListenOverflows},
+		{"TcpExt", "ListenDrops", &counters.TCPListenDrops},
+		{"TcpExt", "TCPTimeouts", &counters.TCPTimeouts},
+		{"Udp", "InDatagrams", &counters.UDPInDatagrams},
+		{"Udp", "OutDatagrams", &counters.UDPOutDatagrams},
+		{"Udp", "NoPorts", &counters.UDPNoPorts},
+		{"Udp", "InErrors", &counters.UDPInErrors},
+		{"Udp", "RcvbufErrors", &counters.UDPRcvbufErrors},
+		{"Udp", "SndbufErrors", &counters.UDPSndbufErrors},
+	} {
+		// counters missing on older kernels stay zero
+		if v := stats[c.proto][c.name]; v > 0 {
+			*c.value = uint64(v)
+		}
+	}
+	return counters, nil
+}
+
+// parse pairs of header and value lines such as "Tcp: RtoAlgorithm ..." and "Tcp: 1 ..."
+func ParseProtoStats(r io.Reader) (map[string]map[string]int64, error) {
+	stats := make(map[string]map[string]int64)
+	scanner := bufio.NewScanner(r)
+	// netstat lines are longer than the default buffer on recent kernels
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		header := strings.Fields(scanner.Text())
+		if len(header) == 0 {
+			continue
+		}
+		if !scanner.Scan() {
+			return nil, fmt.Errorf("missing values for %s", header[0])
+		}
+		values := strings.Fields(scanner.Text())
+		if len(values) != len(header) || values[0] != header[0] {
+			return nil, fmt.Errorf("malformed values for %s", header[0])
+		}
+
+		proto := strings.TrimSuffix(header[0], ":")
+		stats[proto] = make(map[string]int64, len(header)-1)
+		for i := 1; i < len(header); i++ {
+			value, err := strconv.ParseInt(values[i], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value of %s %s: %w", proto, header[i], err)
+			}
+			stats[proto][header[i]] = value
+		}
+	}
+	return stats, scanner.Err()
+}
+
+// growth of socket counters between two samples, nil when any counter was reset
+func SocketCountersDelta(prev, cur models.SocketCounters) *models.SocketCounters {
+	var delta models.SocketCounters
+	pairs := []struct {
+		prev, cur uint64
+		delta     *uint64
+	}{
+		{prev.TCPActiveOpens, cur.TCPActiveOpens, &delta.TCPActiveOpens},
+		{prev.TCPPassiveOpens, cur.TCPPassiveOpens, &delta.TCPPassiveOpens},
+		{prev.TCPAttemptFails, cur.TCPAttemptFails, &delta.TCPAttemptFails},
+		{prev.TCPEstabResets, cur.TCPEstabResets, &delta.TCPEstabResets},
+		{prev.TCPOutSegs, cur.TCPOutSegs, &delta.TCPOutSegs},
+		{prev.TCPRetransSegs, cur.TCPRetransSegs, &delta.TCPRetransSegs},
+		{prev.TCPInErrs, cur.TCPInErrs, &delta.TCPInErrs},
+		{prev.TCPOutRsts, cur.TCPOutRsts, &delta.TCPOutRsts},
+		{prev.TCPListenOverflows, cur.TCPListenOverflows, &delta.TCPListenOverflows},
+		{prev.TCPListenDrops, cur.TCPListenDrops, &delta.TCPListenDrops},
+		{prev.TCPTimeouts, cur.TCPTimeouts, &delta.TCPTimeouts},
+		{prev.UDPInDatagrams, cur.UDPInDatagrams, &delta.UDPInDatagrams},
+		{prev.UDPOutDatagrams, cur.UDPOutDatagrams, &delta.UDPOutDatagrams},
+		{prev.UDPNoPorts, cur.UDPNoPorts, &delta.UDPNoPorts},
+		{prev.UDPInErrors, cur.UDPInErrors, &delta.UDPInErrors},
+		{prev.UDPRcvbufErrors, cur.UDPRcvbufErrors, &delta.UDPRcvbufErrors},
+		{prev.UDPSndbufErrors, cur.UDPSndbufErrors, &delta.UDPSndbufErrors},
+	}
+	for _, p := range pairs {
+		d, ok := counterDelta(p.prev, p.cur)
+		if !ok {
+			return nil
+		}
+		*p.delta = d
+	}
+	return &delta
+}
@@ -0,0 +1,61 @@
+package agent_test
+
+import (
+	"context"
+	"data_agent/internal/agent"
+	"data_agent/internal/models"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCountTCPStates verifies IPv4 and IPv6 sockets from the fixture tables are counted per state
+func TestCountTCPStates(t *testing.T) {
+	states, err := agent.CountTCPStates(context.Background(), "testdata/proc")
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, states["LISTEN"])
+	assert.Equal(t, 1, states["ESTABLISHED"])
+	assert.Equal(t, 2, states["TIME_WAIT"])
+	assert.Equal(t, 1, states["CLOSE_WAIT"])
+}
+
+// TestReadSocketCounters verifies counters are picked from net/snmp and net/netstat
+func TestReadSocketCounters(t *testing.T) {
+	counters, err := agent.ReadSocketCounters("testdata/proc")
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(184213), counters.TCPActiveOpens)
+	assert.Equal(t, uint64(18342), counters.TCPRetransSegs)
+	assert.Equal(t, uint64(311), counters.TCPListenOverflows)
+	assert.Equal(t, uint64(322), counters.TCPListenDrops)
+	assert.Equal(t, uint64(5120), counters.TCPTimeouts)
+	assert.Equal(t, uint64(56), counters.UDPInErrors)
+	assert.Equal(t, uint64(48), counters.UDPRcvbufErrors)
+}
+
+// TestParseProtoStatsInvalid ensures mismatched header and value lines are rejected
+func TestParseProtoStatsInvalid(t *testing.T) {
+	_, err := agent.ParseProtoStats(strings.NewReader("Tcp: ActiveOpens PassiveOpens\nTcp: 1\n"))
+	assert.Error(t, err)
+
+	_, err = agent.ParseProtoStats(strings.NewReader("Tcp: ActiveOpens\n"))
+	assert.Error(t, err)
+}
+
+// TestSocketCountersDelta checks growth between samples and reset detection
+func TestSocketCountersDelta(t *testing.T) {
+	prev := models.SocketCounters{TCPRetransSegs: 100, TCPListenOverflows: 5, UDPInErrors: 1}
+	cur := models.SocketCounters{TCPRetransSegs: 130, TCPListenOverflows: 9, UDPInErrors: 1}
+
+	delta := agent.SocketCountersDelta(prev, cur)
+	require.NotNil(t, delta)
+	assert.Equal(t, uint64(30), delta.TCPRetransSegs)
+	assert.Equal(t, uint64(4), delta.TCPListenOverflows)
+	assert.Zero(t, delta.UDPInErrors)
+
+	// counters restart after a reboot
+	assert.Nil(t, agent.SocketCountersDelta(cur, models.SocketCounters{TCPRetransSegs: 3}))
+}
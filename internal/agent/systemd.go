@@ -0,0 +1,133 @@
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"data_agent/internal/models"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// unit properties read from systemctl show
+var systemdProperties = []string{"Id", "LoadState", "ActiveState", "SubState", "NRestarts", "StateChangeTimestamp"}
+
+// layout of timestamps printed by systemctl show
+const systemdTimeLayout = "Mon 2006-01-02 15:04:05 MST"
+
+// runs systemctl with the given arguments and returns its standard output
+type SystemctlRunner interface {
+	Run(ctx context.Context, args ...string) ([]byte, error)
+}
+
+// runs the systemctl binary of the host
+type execSystemctl struct{}
+
+func (execSystemctl) Run(ctx context.Context, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "systemctl", args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("systemctl show: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// read the state of units, unknown units are reported with load state not-found
+func ReadSystemdUnits(ctx context.Context, runner SystemctlRunner, units []string) ([]models.SystemdUnit, error) {
+	args := []string{"show", "--no-pager", "--property=" + strings.Join(systemdProperties, ","), "--"}
+	out, err := runner.Run(ctx, append(args, units...)...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSystemctlShow(out, time.Local)
+}
+
+// parse property blocks of systemctl show separated by empty lines, timestamps are read in loc
+func ParseSystemctlShow(data []byte, loc *time.Location) ([]models.SystemdUnit, error) {
+	var units []models.SystemdUnit
+	var unit *models.SystemdUnit
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			unit = nil
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid systemctl show line %q", line)
+		}
+		if unit == nil {
+			units = append(units, models.SystemdUnit{})
+			unit = &units[len(units)-1]
+		}
+
+		switch key {
+		case "Id":
+			unit.Name = value
+		case "LoadState":
+			unit.LoadState = value
+		case "ActiveState":
+			unit.ActiveState = value
+		case "SubState":
+			unit.SubState = value
+		case "NRestarts":
+			// missing on systemd older than 235
+			if value != "" {
+				restarts, err := strconv.ParseUint(value, 10, 32)
+				if err != nil {
+					return nil, fmt.Errorf("invalid restart count of %s: %w", unit.Name, err)
+				}
+				unit.Restarts = uint32(restarts)
+			}
+		case "StateChangeTimestamp":
+			// empty when the unit never changed its state since boot
+			if value != "" {
+				t, err := time.ParseInLocation(systemdTimeLayout, value, loc)
+				if err != nil {
+					return nil, fmt.Errorf("invalid state change time of %s: %w", unit.Name, err)
+				}
+				unit.StateChange = &t
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, u := range units {
+		if u.Name == "" {
+			return nil, fmt.Errorf("systemctl show output without unit Id")
+		}
+	}
+	return units, nil
+}
+
+// reports the state of configured systemd units
+type systemdCollector struct {
+	runner SystemctlRunner
+	units  []string
+}
+
+func newSystemdCollector(cfg *Config, runner SystemctlRunner) *systemdCollector {
+	return &systemdCollector{runner: runner, units: cfg.SystemdUnits}
+}
+
+func (c *systemdCollector) Name() string { return "systemd" }
+
+func (c *systemdCollector) Collect(ctx context.Context) (Apply, error) {
+	if len(c.units) == 0 {
+		return nil, nil
+	}
+	units, err := ReadSystemdUnits(ctx, c.runner, c.units)
+	if err != nil {
+		return nil, err
+	}
+	return func(m *models.Metric) { m.SystemdUnits = units }, nil
+}
@@ -0,0 +1,85 @@
+package agent_test
+
+import (
+	"context"
+	"data_agent/internal/agent"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// systemctl runner returning canned output
+type fakeSystemctl struct {
+	out  string
+	err  error
+	args []string
+}
+
+func (f *fakeSystemctl) Run(ctx context.Context, args ...string) ([]byte, error) {
+	f.args = args
+	return []byte(f.out), f.err
+}
+
+const systemctlShowOutput = `Id=nginx.service
+LoadState=loaded
+ActiveState=active
+SubState=running
+NRestarts=0
+StateChangeTimestamp=Tue 2024-03-05 10:15:30 UTC
+
+Id=postgresql.service
+LoadState=loaded
+ActiveState=activating
+SubState=auto-restart
+NRestarts=4
+StateChangeTimestamp=Tue 2024-03-05 11:00:01 UTC
+
+Id=missing.service
+LoadState=not-found
+ActiveState=inactive
+SubState=dead
+NRestarts=
+StateChangeTimestamp=
+`
+
+// TestReadSystemdUnits verifies parsing of systemctl show output
+func TestReadSystemdUnits(t *testing.T) {
+	runner := &fakeSystemctl{out: systemctlShowOutput}
+	units, err := agent.ReadSystemdUnits(context.Background(), runner, []string{"nginx.service", "postgresql.service", "missing.service"})
+	require.NoError(t, err)
+	assert.Equal(t, "show", runner.args[0])
+	assert.Equal(t, []string{"--", "nginx.service", "postgresql.service", "missing.service"}, runner.args[len(runner.args)-4:])
+
+	require.Len(t, units, 3)
+	assert.Equal(t, "nginx.service", units[0].Name)
+	assert.Equal(t, "running", units[0].SubState)
+	require.NotNil(t, units[0].StateChange)
+	assert.True(t, units[0].StateChange.Equal(time.Date(2024, 3, 5, 10, 15, 30, 0, time.UTC)))
+
+	assert.Equal(t, "activating", units[1].ActiveState)
+	assert.Equal(t, "auto-restart", units[1].SubState)
+	assert.Equal(t, uint32(4), units[1].Restarts)
+
+	assert.Equal(t, "not-found", units[2].LoadState)
+	assert.Zero(t, units[2].Restarts)
+	assert.Nil(t, units[2].StateChange)
+}
+
+// TestReadSystemdUnitsErrors verifies that runner failures and malformed output are reported
+func TestReadSystemdUnitsErrors(t *testing.T) {
+	_, err := agent.ReadSystemdUnits(context.Background(), &fakeSystemctl{err: errors.New("no systemd")}, []string{"nginx.service"})
+	assert.Error(t, err)
+
+	for _, out := range []string{
+		"Id=nginx.service\ngarbage\n",
+		"Id=nginx.service\nNRestarts=many\n",
+		"Id=nginx.service\nStateChangeTimestamp=yesterday\n",
+		"ActiveState=active\n",
+	} {
+		_, err := agent.ParseSystemctlShow([]byte(out), time.UTC)
+		assert.Error(t, err, out)
+	}
+}
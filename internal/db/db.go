@@ -5,10 +5,12 @@ import (
 	"data_agent/internal/config"
 	"data_agent/internal/models"
 	"database/sql"
+	_ "embed"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
+	"maps"
 	"time"
 
 	_ "github.com/lib/pq"
@@ -44,9 +46,47 @@ func InitDB() (*sql.DB, error) {
 	}
 
 	log.Println("Successfully connected to the database")
+
+	// databases created by earlier versions get the tables and columns added since
+	mctx, mcancel := context.WithTimeout(context.Background(), time.Minute)
+	defer mcancel()
+	if err := Migrate(mctx, db); err != nil {
+		db.Close()
+		return nil, err
+	}
 	return db, nil
 }
 
+// idempotent schema, applied by the postgres image on a fresh volume and by Migrate on every start
+//
+//go:embed schema.sql
+var schema string
+
+// bring the schema of an existing database up to date, safe to run concurrently from several services
+func Migrate(ctx context.Context, db *sql.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin migration: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			log.Printf("migration rollback error: %v", err)
+		}
+	}()
+
+	// concurrent CREATE ... IF NOT EXISTS statements can still collide, so services migrate one at a time
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtext('data_agent_schema'))"); err != nil {
+		return fmt.Errorf("lock schema: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, schema); err != nil {
+		return fmt.Errorf("migrate schema: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit migration: %w", err)
+	}
+	return nil
+}
+
 // insert host and metric into database
 func SaveMetric(ctx context.Context, db *sql.DB, metric *models.MetricMessage) error {
 	// transactions for secure queries
@@ -62,59 +102,181 @@ func SaveMetric(ctx context.Context, db *sql.DB, metric *models.MetricMessage) e
 		}
 	}()
 
-	var hostID int64
-	// check if host exists in database
-	err = tx.QueryRowContext(ctx, "SELECT id FROM hosts WHERE hostname=$1", metric.Host.Hostname).Scan(&hostID)
-	if errors.Is(err, sql.ErrNoRows) {
-		// insert host into database when not exists
-		err = tx.QueryRowContext(
-			ctx,
-			`INSERT INTO hosts (hostname, os, platform, platform_ver, kernel_ver) 
-			VALUES ($1, $2, $3, $4, $5)
-			RETURNING id`,
-			metric.Host.Hostname,
-			metric.Host.OS,
-			metric.Host.Platform,
-			metric.Host.PlatformVer,
-			metric.Host.KernelVer,
-		).Scan(&hostID)
-		if err != nil {
-			return fmt.Errorf("insert host info: %w", err)
-		}
-	} else if err != nil {
-		return fmt.Errorf("select host_id: %w", err)
+	hostID, err := saveHost(ctx, tx, &metric.Host, metric.Metric.Time)
+	if err != nil {
+		return err
 	}
 
 	// set host_id in metric
 	metric.Metric.HostID = hostID
 
-	// marshaling disk and network slices to JSON
+	// marshaling metric details to JSON
+	cpuCoresJSON, err := json.Marshal(metric.Metric.CPUCores)
+	if err != nil {
+		return fmt.Errorf("marshal cpu cores: %w", err)
+	}
+	cpuTimesJSON, err := json.Marshal(metric.Metric.CPUTimes)
+	if err != nil {
+		return fmt.Errorf("marshal cpu times: %w", err)
+	}
+	memoryJSON, err := json.Marshal(metric.Metric.Memory)
+	if err != nil {
+		return fmt.Errorf("marshal memory metrics: %w", err)
+	}
 	diskJSON, err := json.Marshal(metric.Metric.Disk)
 	if err != nil {
 		return fmt.Errorf("marshal disk metrics: %w", err)
 	}
+	diskIOJSON, err := json.Marshal(metric.Metric.DiskIO)
+	if err != nil {
+		return fmt.Errorf("marshal disk io metrics: %w", err)
+	}
 	networkJSON, err := json.Marshal(metric.Metric.Network)
 	if err != nil {
 		return fmt.Errorf("marshal network metrics: %w", err)
 	}
+	processesJSON, err := json.Marshal(metric.Metric.Processes)
+	if err != nil {
+		return fmt.Errorf("marshal process metrics: %w", err)
+	}
+	pressureJSON, err := json.Marshal(metric.Metric.Pressure)
+	if err != nil {
+		return fmt.Errorf("marshal pressure metrics: %w", err)
+	}
+	sensorsJSON, err := json.Marshal(metric.Metric.Sensors)
+	if err != nil {
+		return fmt.Errorf("marshal sensor metrics: %w", err)
+	}
+	socketsJSON, err := json.Marshal(metric.Metric.Sockets)
+	if err != nil {
+		return fmt.Errorf("marshal socket metrics: %w", err)
+	}
+	pluginsJSON, err := json.Marshal(metric.Metric.Plugins)
+	if err != nil {
+		return fmt.Errorf("marshal plugin results: %w", err)
+	}
+	logsJSON, err := json.Marshal(metric.Metric.Logs)
+	if err != nil {
+		return fmt.Errorf("marshal log metrics: %w", err)
+	}
+
+	// cpu usage is NULL on the first sample of an agent
+	var cpuUsage sql.NullFloat64
+	if metric.Metric.CPU != nil {
+		cpuUsage = sql.NullFloat64{Float64: *metric.Metric.CPU, Valid: true}
+	}
+
+	// load averages are NULL when the load collector did not run
+	var load1, load5, load15 sql.NullFloat64
+	if l := metric.Metric.Load; l != nil {
+		load1 = sql.NullFloat64{Float64: l.Load1, Valid: true}
+		load5 = sql.NullFloat64{Float64: l.Load5, Valid: true}
+		load15 = sql.NullFloat64{Float64: l.Load15, Valid: true}
+	}
 
 	// insert metric into database
-	_, err = tx.ExecContext(
+	err = tx.QueryRowContext(
 		ctx,
-		`INSERT INTO metrics (host_id, uptime, cpu, ram, disk, network, time)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		`INSERT INTO metrics (host_id, uptime, cpu, cpu_cores, cpu_times, load1, load5, load15, ram, memory, disk, disk_io, network, processes, pressure, sensors, sockets, plugins, logs, time)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
+		RETURNING id`,
 		metric.Metric.HostID,
 		metric.Metric.Uptime,
-		metric.Metric.CPU,
+		cpuUsage,
+		cpuCoresJSON,
+		cpuTimesJSON,
+		load1,
+		load5,
+		load15,
 		metric.Metric.RAM,
+		memoryJSON,
 		diskJSON,
+		diskIOJSON,
 		networkJSON,
+		processesJSON,
+		pressureJSON,
+		sensorsJSON,
+		socketsJSON,
+		pluginsJSON,
+		logsJSON,
 		metric.Metric.Time,
-	)
+	).Scan(&metric.Metric.ID)
 	if err != nil {
 		return fmt.Errorf("insert metric: %w", err)
 	}
 
+	// cgroups are stored per row so they can be queried by path and container
+	for _, cg := range metric.Metric.Cgroups {
+		_, err = tx.ExecContext(
+			ctx,
+			`INSERT INTO cgroup_metrics (metric_id, host_id, path, container_id, cpu_usage_usec, cpu_user_usec, cpu_system_usec, cpu_percent,
+				nr_periods, nr_throttled, throttled_usec, memory_current, memory_max, io_read_bytes, io_write_bytes, io_read_ops, io_write_ops, time)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)`,
+			metric.Metric.ID,
+			hostID,
+			cg.Path,
+			cg.ContainerID,
+			cg.CPUUsageUsec,
+			cg.CPUUserUsec,
+			cg.CPUSystemUsec,
+			cg.CPUPercent,
+			cg.NrPeriods,
+			cg.NrThrottled,
+			cg.ThrottledUsec,
+			cg.MemoryCurrent,
+			cg.MemoryMax,
+			cg.IOReadBytes,
+			cg.IOWriteBytes,
+			cg.IOReadOps,
+			cg.IOWriteOps,
+			metric.Metric.Time,
+		)
+		if err != nil {
+			return fmt.Errorf("insert cgroup metric %s: %w", cg.Path, err)
+		}
+	}
+
+	for _, probe := range metric.Metric.Probes {
+		_, err = tx.ExecContext(
+			ctx,
+			`INSERT INTO probe_results (metric_id, host_id, name, type, target, success, latency_ms, status_code, error, time)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+			metric.Metric.ID,
+			hostID,
+			probe.Name,
+			probe.Type,
+			probe.Target,
+			probe.Success,
+			probe.LatencyMs,
+			probe.StatusCode,
+			probe.Error,
+			probe.Time,
+		)
+		if err != nil {
+			return fmt.Errorf("insert probe result %s: %w", probe.Name, err)
+		}
+	}
+
+	for _, unit := range metric.Metric.SystemdUnits {
+		_, err = tx.ExecContext(
+			ctx,
+			`INSERT INTO systemd_units (metric_id, host_id, unit, load_state, active_state, sub_state, restarts, state_change, time)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+			metric.Metric.ID,
+			hostID,
+			unit.Name,
+			unit.LoadState,
+			unit.ActiveState,
+			unit.SubState,
+			unit.Restarts,
+			unit.StateChange,
+			metric.Metric.Time,
+		)
+		if err != nil {
+			return fmt.Errorf("insert systemd unit %s: %w", unit.Name, err)
+		}
+	}
+
 	// commit transaction when all saved
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("commit transaction: %w", err)
@@ -122,3 +284,244 @@ func SaveMetric(ctx context.Context, db *sql.DB, metric *models.MetricMessage) e
 
 	return nil
 }
+
+// find or register the host of a metric sampled at the given time, update its metadata and return its id
+func saveHost(ctx context.Context, tx *sql.Tx, host *models.Host, at time.Time) (int64, error) {
+	var stored models.Host
+	var err error
+	if host.MachineID == "" {
+		// agents without a machine ID are matched by hostname as before
+		stored, err = selectHost(ctx, tx, "hostname=$1 AND machine_id IS NULL", host.Hostname)
+	} else {
+		stored, err = selectHost(ctx, tx, "machine_id=$1", host.MachineID)
+		if errors.Is(err, sql.ErrNoRows) {
+			// adopt a host registered by hostname before machine IDs were reported
+			stored, err = selectHost(ctx, tx, "hostname=$1 AND machine_id IS NULL ORDER BY id LIMIT 1", host.Hostname)
+			if err == nil {
+				if _, err := tx.ExecContext(ctx, "UPDATE hosts SET machine_id=$1 WHERE id=$2", host.MachineID, stored.ID); err != nil {
+					return 0, fmt.Errorf("adopt host by hostname: %w", err)
+				}
+			}
+		}
+	}
+	var hostID int64
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		if hostID, err = insertHost(ctx, tx, host); err != nil {
+			return 0, err
+		}
+	case err != nil:
+		return 0, fmt.Errorf("select host: %w", err)
+	default:
+		if err := updateHost(ctx, tx, stored, host, at); err != nil {
+			return 0, err
+		}
+		hostID = stored.ID
+	}
+
+	if err := saveAgentLabels(ctx, tx, hostID, host.Labels); err != nil {
+		return 0, err
+	}
+
+	// agents send the inventory only on startup and when it changes
+	if host.Inventory != nil {
+		if err := saveInventory(ctx, tx, hostID, host.Inventory); err != nil {
+			return 0, err
+		}
+	}
+	return hostID, nil
+}
+
+// replace the labels configured on the agent when they differ from the stored ones
+func saveAgentLabels(ctx context.Context, tx *sql.Tx, hostID int64, labels map[string]string) error {
+	stored, err := selectLabels(ctx, tx, hostID, models.LabelSourceAgent)
+	if err != nil {
+		return err
+	}
+	if maps.Equal(stored, labels) {
+		return nil
+	}
+	return replaceLabels(ctx, tx, hostID, models.LabelSourceAgent, labels)
+}
+
+// labels of a host from one source
+func selectLabels(ctx context.Context, tx *sql.Tx, hostID int64, source string) (map[string]string, error) {
+	rows, err := tx.QueryContext(ctx, "SELECT key, value FROM host_labels WHERE host_id=$1 AND source=$2", hostID, source)
+	if err != nil {
+		return nil, fmt.Errorf("select %s labels: %w", source, err)
+	}
+	defer rows.Close()
+
+	labels := make(map[string]string)
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, fmt.Errorf("scan %s labels: %w", source, err)
+		}
+		labels[key] = value
+	}
+	return labels, rows.Err()
+}
+
+// replace all labels of a host from one source
+func replaceLabels(ctx context.Context, tx *sql.Tx, hostID int64, source string, labels map[string]string) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM host_labels WHERE host_id=$1 AND source=$2", hostID, source); err != nil {
+		return fmt.Errorf("delete %s labels: %w", source, err)
+	}
+	for key, value := range labels {
+		_, err := tx.ExecContext(
+			ctx,
+			"INSERT INTO host_labels (host_id, key, value, source) VALUES ($1, $2, $3, $4)",
+			hostID,
+			key,
+			value,
+			source,
+		)
+		if err != nil {
+			return fmt.Errorf("insert %s label %s: %w", source, key, err)
+		}
+	}
+	return nil
+}
+
+// replace the labels of a host set through the API
+func SetHostLabels(ctx context.Context, db *sql.DB, hostID int64, labels map[string]string) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			log.Printf("transaction rollback error: %v", err)
+		}
+	}()
+
+	if err := replaceLabels(ctx, tx, hostID, models.LabelSourceAPI, labels); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// overwrite the hardware and platform details of a host
+func saveInventory(ctx context.Context, tx *sql.Tx, hostID int64, inv *models.HostInventory) error {
+	interfacesJSON, err := json.Marshal(inv.Interfaces)
+	if err != nil {
+		return fmt.Errorf("marshal network interfaces: %w", err)
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		`UPDATE hosts SET cpu_model=$1, cpu_cores=$2, cpu_threads=$3, memory_total=$4, interfaces=$5,
+			boot_time=$6, virtualization=$7, virtualization_role=$8, arch=$9
+		WHERE id=$10`,
+		inv.CPUModel,
+		inv.CPUCores,
+		inv.CPUThreads,
+		inv.MemoryTotal,
+		interfacesJSON,
+		inv.BootTime,
+		inv.Virtualization,
+		inv.VirtualizationRole,
+		inv.Arch,
+		hostID,
+	)
+	if err != nil {
+		return fmt.Errorf("update host inventory: %w", err)
+	}
+	return nil
+}
+
+// select a stored host matching a condition on the hosts table
+func selectHost(ctx context.Context, tx *sql.Tx, where string, args ...any) (models.Host, error) {
+	var host models.Host
+	err := tx.QueryRowContext(
+		ctx,
+		`SELECT id, hostname, COALESCE(os, ''), COALESCE(platform, ''), COALESCE(platform_ver, ''), COALESCE(kernel_ver, '')
+		FROM hosts WHERE `+where,
+		args...,
+	).Scan(&host.ID, &host.Hostname, &host.OS, &host.Platform, &host.PlatformVer, &host.KernelVer)
+	return host, err
+}
+
+// overwrite changed host metadata and record every change at the time of the metric that reported it
+func updateHost(ctx context.Context, tx *sql.Tx, stored models.Host, host *models.Host, at time.Time) error {
+	changes := []struct {
+		field, oldValue, newValue string
+	}{
+		{"hostname", stored.Hostname, host.Hostname},
+		{"os", stored.OS, host.OS},
+		{"platform", stored.Platform, host.Platform},
+		{"platform_ver", stored.PlatformVer, host.PlatformVer},
+		{"kernel_ver", stored.KernelVer, host.KernelVer},
+	}
+
+	changed := false
+	for _, c := range changes {
+		if c.oldValue == c.newValue {
+			continue
+		}
+		if err := recordHostChange(ctx, tx, stored.ID, c.field, c.oldValue, c.newValue, at); err != nil {
+			return err
+		}
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	_, err := tx.ExecContext(
+		ctx,
+		"UPDATE hosts SET hostname=$1, os=$2, platform=$3, platform_ver=$4, kernel_ver=$5 WHERE id=$6",
+		host.Hostname,
+		host.OS,
+		host.Platform,
+		host.PlatformVer,
+		host.KernelVer,
+		stored.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("update host info: %w", err)
+	}
+	return nil
+}
+
+// insert a new host and return its id
+func insertHost(ctx context.Context, tx *sql.Tx, host *models.Host) (int64, error) {
+	var hostID int64
+	err := tx.QueryRowContext(
+		ctx,
+		`INSERT INTO hosts (machine_id, hostname, os, platform, platform_ver, kernel_ver)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id`,
+		sql.NullString{String: host.MachineID, Valid: host.MachineID != ""},
+		host.Hostname,
+		host.OS,
+		host.Platform,
+		host.PlatformVer,
+		host.KernelVer,
+	).Scan(&hostID)
+	if err != nil {
+		return 0, fmt.Errorf("insert host info: %w", err)
+	}
+	return hostID, nil
+}
+
+// record a changed host attribute
+func recordHostChange(ctx context.Context, tx *sql.Tx, hostID int64, field, oldValue, newValue string, at time.Time) error {
+	_, err := tx.ExecContext(
+		ctx,
+		"INSERT INTO host_changes (host_id, field, old_value, new_value, time) VALUES ($1, $2, $3, $4, $5)",
+		hostID,
+		field,
+		oldValue,
+		newValue,
+		at,
+	)
+	if err != nil {
+		return fmt.Errorf("record %s change: %w", field, err)
+	}
+	return nil
+}
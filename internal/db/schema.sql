@@ -1,19 +1,157 @@
 CREATE TABLE IF NOT EXISTS hosts (
     id SERIAL PRIMARY KEY,
-    hostname TEXT UNIQUE NOT NULL,
+    machine_id TEXT,
+    hostname TEXT NOT NULL,
     os TEXT,
     platform TEXT,
     platform_ver TEXT,
-    kernel_ver TEXT
+    kernel_ver TEXT,
+    cpu_model TEXT,
+    cpu_cores INTEGER,
+    cpu_threads INTEGER,
+    memory_total BIGINT,
+    interfaces JSONB,
+    boot_time TIMESTAMPTZ,
+    virtualization TEXT,
+    virtualization_role TEXT,
+    arch TEXT
 );
 
+-- hosts created before machine IDs were reported were unique by hostname,
+-- now several hosts may share a hostname and are told apart by machine ID
+ALTER TABLE hosts DROP CONSTRAINT IF EXISTS hosts_hostname_key;
+ALTER TABLE hosts ADD COLUMN IF NOT EXISTS machine_id TEXT;
+CREATE UNIQUE INDEX IF NOT EXISTS hosts_machine_id_idx ON hosts (machine_id);
+CREATE INDEX IF NOT EXISTS hosts_hostname_idx ON hosts (hostname);
+
+-- host inventory
+ALTER TABLE hosts ADD COLUMN IF NOT EXISTS cpu_model TEXT;
+ALTER TABLE hosts ADD COLUMN IF NOT EXISTS cpu_cores INTEGER;
+ALTER TABLE hosts ADD COLUMN IF NOT EXISTS cpu_threads INTEGER;
+ALTER TABLE hosts ADD COLUMN IF NOT EXISTS memory_total BIGINT;
+ALTER TABLE hosts ADD COLUMN IF NOT EXISTS interfaces JSONB;
+ALTER TABLE hosts ADD COLUMN IF NOT EXISTS boot_time TIMESTAMPTZ;
+ALTER TABLE hosts ADD COLUMN IF NOT EXISTS virtualization TEXT;
+ALTER TABLE hosts ADD COLUMN IF NOT EXISTS virtualization_role TEXT;
+ALTER TABLE hosts ADD COLUMN IF NOT EXISTS arch TEXT;
+
+CREATE TABLE IF NOT EXISTS host_labels (
+    host_id BIGINT REFERENCES hosts(id) ON DELETE CASCADE,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    source TEXT NOT NULL CHECK (source IN ('agent', 'api')),
+    PRIMARY KEY (host_id, key, source)
+);
+
+CREATE INDEX IF NOT EXISTS host_labels_key_value_idx ON host_labels (key, value);
+
+-- labels set through the API override agent labels with the same key
+CREATE OR REPLACE VIEW host_labels_effective AS
+SELECT DISTINCT ON (host_id, key) host_id, key, value, source
+FROM host_labels
+ORDER BY host_id, key, (source = 'api') DESC;
+
+CREATE TABLE IF NOT EXISTS host_changes (
+    id SERIAL PRIMARY KEY,
+    host_id BIGINT REFERENCES hosts(id) ON DELETE CASCADE,
+    field TEXT NOT NULL,
+    old_value TEXT,
+    new_value TEXT,
+    time TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS host_changes_host_time_idx ON host_changes (host_id, time DESC);
+
 CREATE TABLE IF NOT EXISTS metrics (
     id SERIAL PRIMARY KEY,
     host_id BIGINT REFERENCES hosts(id) ON DELETE CASCADE,
     uptime BIGINT,
     cpu NUMERIC,
+    cpu_cores JSONB,
+    cpu_times JSONB,
+    load1 NUMERIC,
+    load5 NUMERIC,
+    load15 NUMERIC,
     ram NUMERIC,
+    memory JSONB,
     disk JSONB,
+    disk_io JSONB,
     network JSONB,
+    processes JSONB,
+    pressure JSONB,
+    sensors JSONB,
+    sockets JSONB,
+    plugins JSONB,
+    logs JSONB,
     time TIMESTAMPTZ
 );
+
+-- columns added after the first release, CREATE TABLE IF NOT EXISTS leaves existing tables unchanged
+ALTER TABLE metrics ADD COLUMN IF NOT EXISTS cpu_cores JSONB;
+ALTER TABLE metrics ADD COLUMN IF NOT EXISTS cpu_times JSONB;
+ALTER TABLE metrics ADD COLUMN IF NOT EXISTS load1 NUMERIC;
+ALTER TABLE metrics ADD COLUMN IF NOT EXISTS load5 NUMERIC;
+ALTER TABLE metrics ADD COLUMN IF NOT EXISTS load15 NUMERIC;
+ALTER TABLE metrics ADD COLUMN IF NOT EXISTS disk_io JSONB;
+ALTER TABLE metrics ADD COLUMN IF NOT EXISTS memory JSONB;
+ALTER TABLE metrics ADD COLUMN IF NOT EXISTS processes JSONB;
+ALTER TABLE metrics ADD COLUMN IF NOT EXISTS pressure JSONB;
+ALTER TABLE metrics ADD COLUMN IF NOT EXISTS sensors JSONB;
+ALTER TABLE metrics ADD COLUMN IF NOT EXISTS sockets JSONB;
+ALTER TABLE metrics ADD COLUMN IF NOT EXISTS plugins JSONB;
+ALTER TABLE metrics ADD COLUMN IF NOT EXISTS logs JSONB;
+
+CREATE TABLE IF NOT EXISTS cgroup_metrics (
+    id SERIAL PRIMARY KEY,
+    metric_id BIGINT REFERENCES metrics(id) ON DELETE CASCADE,
+    host_id BIGINT REFERENCES hosts(id) ON DELETE CASCADE,
+    path TEXT NOT NULL,
+    container_id TEXT,
+    cpu_usage_usec BIGINT,
+    cpu_user_usec BIGINT,
+    cpu_system_usec BIGINT,
+    cpu_percent NUMERIC,
+    nr_periods BIGINT,
+    nr_throttled BIGINT,
+    throttled_usec BIGINT,
+    memory_current BIGINT,
+    memory_max BIGINT,
+    io_read_bytes BIGINT,
+    io_write_bytes BIGINT,
+    io_read_ops BIGINT,
+    io_write_ops BIGINT,
+    time TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS cgroup_metrics_host_time_idx ON cgroup_metrics (host_id, time DESC);
+
+CREATE TABLE IF NOT EXISTS probe_results (
+    id SERIAL PRIMARY KEY,
+    metric_id BIGINT REFERENCES metrics(id) ON DELETE CASCADE,
+    host_id BIGINT REFERENCES hosts(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    type TEXT NOT NULL,
+    target TEXT NOT NULL,
+    success BOOLEAN NOT NULL,
+    latency_ms NUMERIC,
+    status_code INTEGER,
+    error TEXT,
+    time TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS probe_results_host_time_idx ON probe_results (host_id, time DESC);
+
+CREATE TABLE IF NOT EXISTS systemd_units (
+    id SERIAL PRIMARY KEY,
+    metric_id BIGINT REFERENCES metrics(id) ON DELETE CASCADE,
+    host_id BIGINT REFERENCES hosts(id) ON DELETE CASCADE,
+    unit TEXT NOT NULL,
+    load_state TEXT NOT NULL,
+    active_state TEXT NOT NULL,
+    sub_state TEXT NOT NULL,
+    restarts INTEGER NOT NULL DEFAULT 0,
+    state_change TIMESTAMPTZ,
+    time TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS systemd_units_host_time_idx ON systemd_units (host_id, time DESC);
@@ -0,0 +1,48 @@
+package grpcserver
+
+import (
+	"context"
+	"data_agent/proto"
+)
+
+// retrieves cgroup usage of the latest sample of a host
+func (s *MetricService) ListCgroups(ctx context.Context, req *proto.HostName) (*proto.CgroupList, error) {
+	hostID, err := resolveHost(ctx, s.DB, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// query the cgroups of the newest metric that carries any
+	rows, err := s.DB.Query(`
+		SELECT c.path, COALESCE(c.container_id, ''), c.cpu_usage_usec, c.cpu_user_usec, c.cpu_system_usec, c.cpu_percent,
+			c.nr_periods, c.nr_throttled, c.throttled_usec, c.memory_current, c.memory_max,
+			c.io_read_bytes, c.io_write_bytes, c.io_read_ops, c.io_write_ops, c.time
+		FROM cgroup_metrics c
+		WHERE c.metric_id = (
+			SELECT c2.metric_id
+			FROM cgroup_metrics c2
+			WHERE c2.host_id = $1
+			ORDER BY c2.time DESC
+			LIMIT 1
+		)
+		ORDER BY c.path
+	`, hostID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := &proto.CgroupList{}
+	for rows.Next() {
+		var cg proto.Cgroup
+		if err := rows.Scan(
+			&cg.Path, &cg.ContainerId, &cg.CpuUsageUsec, &cg.CpuUserUsec, &cg.CpuSystemUsec, &cg.CpuPercent,
+			&cg.NrPeriods, &cg.NrThrottled, &cg.ThrottledUsec, &cg.MemoryCurrent, &cg.MemoryMax,
+			&cg.IoReadBytes, &cg.IoWriteBytes, &cg.IoReadOps, &cg.IoWriteOps, &list.Time,
+		); err != nil {
+			return nil, err
+		}
+		list.Cgroups = append(list.Cgroups, &cg)
+	}
+	return list, rows.Err()
+}
@@ -0,0 +1,4 @@
+package grpcserver
+
+// SelectorCondition exposes selectorCondition to tests
+var SelectorCondition = selectorCondition
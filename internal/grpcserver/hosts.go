@@ -2,8 +2,13 @@ package grpcserver
 
 import (
 	"context"
+	"data_agent/internal/models"
 	"data_agent/proto"
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
 )
 
 type HostService struct {
@@ -11,10 +16,21 @@ type HostService struct {
 	DB *sql.DB
 }
 
+// columns of a host row in the order scanned by scanHost
+const hostColumns = `id, COALESCE(machine_id, ''), hostname, os, platform, platform_ver, kernel_ver,
+	COALESCE(cpu_model, ''), COALESCE(cpu_cores, 0), COALESCE(cpu_threads, 0), COALESCE(memory_total, 0),
+	COALESCE(interfaces::text, 'null'), boot_time, COALESCE(virtualization, ''), COALESCE(virtualization_role, ''),
+	COALESCE(arch, ''), ` + hostLabelsColumn
+
 // retrieves all hosts from the database
-func (s *HostService) ListHosts(ctx context.Context, _ *proto.Empty) (*proto.HostList, error) {
-	// query all hosts from database
-	rows, err := s.DB.Query(`SELECT id, hostname, os, platform, platform_ver, kernel_ver FROM hosts`)
+func (s *HostService) ListHosts(ctx context.Context, req *proto.HostFilter) (*proto.HostList, error) {
+	condition, args, err := selectorCondition(req.Selector, "hosts.id", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// query all hosts matching the label selector from database
+	rows, err := s.DB.Query(`SELECT `+hostColumns+` FROM hosts WHERE `+condition, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -22,26 +38,128 @@ func (s *HostService) ListHosts(ctx context.Context, _ *proto.Empty) (*proto.Hos
 
 	var hosts []*proto.Host
 	for rows.Next() {
-		var host proto.Host
-		if err := rows.Scan(&host.Id, &host.Hostname, &host.Os, &host.Platform, &host.PlatformVer, &host.KernelVer); err != nil {
+		host, err := scanHost(rows)
+		if err != nil {
 			return nil, err
 		}
-		hosts = append(hosts, &host)
+		hosts = append(hosts, host)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
 	return &proto.HostList{Hosts: hosts}, nil
 }
 
-// retrieves a specific host by hostname
+// retrieves a specific host by id, machine ID or hostname
 func (s *HostService) GetHost(ctx context.Context, req *proto.HostName) (*proto.Host, error) {
-	var host proto.Host
+	hostID, err := resolveHost(ctx, s.DB, req)
+	if err != nil {
+		return nil, err
+	}
+	return scanHost(s.DB.QueryRowContext(ctx, `SELECT `+hostColumns+` FROM hosts WHERE id=$1`, hostID))
+}
 
-	// query host from database
-	err := s.DB.QueryRow(
-		`SELECT id, hostname, os, platform, platform_ver, kernel_ver FROM hosts WHERE hostname=$1`, req.Hostname,
-	).Scan(&host.Id, &host.Hostname, &host.Os, &host.Platform, &host.PlatformVer, &host.KernelVer)
+// build an SQL condition selecting hosts by id, machine ID and hostname, unset values match every host,
+// placeholders are numbered after the args already passed
+func hostCondition(table string, hostID int64, machineID, hostname string, args []any) (string, []any) {
+	args = append(args, hostID, machineID, hostname)
+	n := len(args)
+	condition := fmt.Sprintf(
+		"($%d::bigint = 0 OR %s.id = $%d) AND ($%d::text = '' OR %s.machine_id = $%d) AND ($%d::text = '' OR %s.hostname = $%d)",
+		n-2, table, n-2, n-1, table, n-1, n, table, n,
+	)
+	return condition, args
+}
+
+// id of the host selected by id, machine ID or hostname,
+// hostnames are not unique and a shared one must be narrowed down by id or machine ID
+func resolveHost(ctx context.Context, db *sql.DB, req *proto.HostName) (int64, error) {
+	if req.HostId == 0 && req.MachineId == "" && req.Hostname == "" {
+		return 0, errors.New("host_id, machine_id or hostname is required")
+	}
+	condition, args := hostCondition("hosts", req.HostId, req.MachineId, req.Hostname, nil)
+	rows, err := db.QueryContext(ctx, `SELECT id FROM hosts WHERE `+condition+` ORDER BY id LIMIT 2`, args...)
 	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return 0, err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	switch len(ids) {
+	case 0:
+		return 0, sql.ErrNoRows
+	case 1:
+		return ids[0], nil
+	default:
+		return 0, fmt.Errorf("hostname %q is shared by several hosts, select one by host_id or machine_id", req.Hostname)
+	}
+}
+
+// scan a host row selected with hostColumns
+func scanHost(row interface{ Scan(dest ...any) error }) (*proto.Host, error) {
+	var host proto.Host
+	var interfaces, labels string
+	var bootTime sql.NullTime
+	if err := row.Scan(
+		&host.Id, &host.MachineId, &host.Hostname, &host.Os, &host.Platform, &host.PlatformVer, &host.KernelVer,
+		&host.CpuModel, &host.CpuCores, &host.CpuThreads, &host.MemoryTotal,
+		&interfaces, &bootTime, &host.Virtualization, &host.VirtualizationRole,
+		&host.Arch, &labels,
+	); err != nil {
 		return nil, err
 	}
+
+	if bootTime.Valid {
+		host.BootTime = bootTime.Time.Format(time.RFC3339)
+	}
+	var ifaces []models.NetInterface
+	if err := json.Unmarshal([]byte(interfaces), &ifaces); err != nil {
+		return nil, fmt.Errorf("decode network interfaces: %w", err)
+	}
+	for _, iface := range ifaces {
+		host.Interfaces = append(host.Interfaces, &proto.NetInterface{Name: iface.Name, Mac: iface.MAC, Addrs: iface.Addrs})
+	}
+	if err := json.Unmarshal([]byte(labels), &host.Labels); err != nil {
+		return nil, fmt.Errorf("decode host labels: %w", err)
+	}
 	return &host, nil
 }
+
+// retrieves recorded metadata changes of a host, newest first
+func (s *HostService) GetHostHistory(ctx context.Context, req *proto.HostName) (*proto.HostHistory, error) {
+	hostID, err := resolveHost(ctx, s.DB, req)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.DB.Query(`
+		SELECT c.field, COALESCE(c.old_value, ''), COALESCE(c.new_value, ''), c.time
+		FROM host_changes c
+		WHERE c.host_id = $1
+		ORDER BY c.time DESC, c.id DESC
+	`, hostID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := &proto.HostHistory{}
+	for rows.Next() {
+		var change proto.HostChange
+		if err := rows.Scan(&change.Field, &change.OldValue, &change.NewValue, &change.Time); err != nil {
+			return nil, err
+		}
+		history.Changes = append(history.Changes, &change)
+	}
+	return history, rows.Err()
+}
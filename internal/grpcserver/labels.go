@@ -0,0 +1,60 @@
+package grpcserver
+
+import (
+	"context"
+	"data_agent/internal/db"
+	"data_agent/internal/models"
+	"data_agent/proto"
+	"fmt"
+	"strings"
+)
+
+// effective labels of a host as a JSON object
+const hostLabelsColumn = `COALESCE((SELECT jsonb_object_agg(l.key, l.value) FROM host_labels_effective l WHERE l.host_id = hosts.id)::text, '{}')`
+
+// build an SQL condition on the host id column from a label selector,
+// placeholders are numbered after the args already passed
+func selectorCondition(selector, hostIDColumn string, args []any) (string, []any, error) {
+	reqs, err := models.ParseSelector(selector)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(reqs) == 0 {
+		return "TRUE", args, nil
+	}
+
+	conditions := make([]string, 0, len(reqs))
+	for _, req := range reqs {
+		args = append(args, req.Key)
+		match := fmt.Sprintf("SELECT 1 FROM host_labels_effective l WHERE l.host_id = %s AND l.key = $%d", hostIDColumn, len(args))
+		if req.Operator == models.SelectorEquals || req.Operator == models.SelectorNotEquals {
+			args = append(args, req.Value)
+			match += fmt.Sprintf(" AND l.value = $%d", len(args))
+		}
+
+		switch req.Operator {
+		case models.SelectorEquals, models.SelectorExists:
+			conditions = append(conditions, "EXISTS ("+match+")")
+		default:
+			// hosts without the label match != like in Kubernetes selectors
+			conditions = append(conditions, "NOT EXISTS ("+match+")")
+		}
+	}
+	return strings.Join(conditions, " AND "), args, nil
+}
+
+// replaces the labels of a host set through the API, agent labels are kept
+func (s *HostService) SetHostLabels(ctx context.Context, req *proto.SetHostLabelsRequest) (*proto.Host, error) {
+	if err := models.ValidateLabels(req.Labels); err != nil {
+		return nil, err
+	}
+
+	hostID, err := resolveHost(ctx, s.DB, &proto.HostName{Hostname: req.Hostname, HostId: req.HostId, MachineId: req.MachineId})
+	if err != nil {
+		return nil, err
+	}
+	if err := db.SetHostLabels(ctx, s.DB, hostID, req.Labels); err != nil {
+		return nil, err
+	}
+	return s.GetHost(ctx, &proto.HostName{HostId: hostID})
+}
@@ -0,0 +1,53 @@
+package grpcserver_test
+
+import (
+	"testing"
+
+	"data_agent/internal/grpcserver"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectorConditionEmpty(t *testing.T) {
+	condition, args, err := grpcserver.SelectorCondition("", "hosts.id", []any{10})
+	require.NoError(t, err)
+	assert.Equal(t, "TRUE", condition)
+	assert.Equal(t, []any{10}, args)
+}
+
+func TestSelectorConditionEquals(t *testing.T) {
+	condition, args, err := grpcserver.SelectorCondition("env==prod", "hosts.id", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "EXISTS (SELECT 1 FROM host_labels_effective l WHERE l.host_id = hosts.id AND l.key = $1 AND l.value = $2)", condition)
+	assert.Equal(t, []any{"env", "prod"}, args)
+}
+
+func TestSelectorConditionNotEquals(t *testing.T) {
+	// hosts without the label match as well, so the condition negates an equality match
+	condition, args, err := grpcserver.SelectorCondition("role!=db", "hosts.id", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "NOT EXISTS (SELECT 1 FROM host_labels_effective l WHERE l.host_id = hosts.id AND l.key = $1 AND l.value = $2)", condition)
+	assert.Equal(t, []any{"role", "db"}, args)
+}
+
+func TestSelectorConditionExists(t *testing.T) {
+	condition, args, err := grpcserver.SelectorCondition("team,!canary", "h.id", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "EXISTS (SELECT 1 FROM host_labels_effective l WHERE l.host_id = h.id AND l.key = $1)"+
+		" AND NOT EXISTS (SELECT 1 FROM host_labels_effective l WHERE l.host_id = h.id AND l.key = $2)", condition)
+	assert.Equal(t, []any{"team", "canary"}, args)
+}
+
+func TestSelectorConditionNumbersAfterArgs(t *testing.T) {
+	condition, args, err := grpcserver.SelectorCondition("env=prod,!canary", "h.id", []any{int32(50), "host1"})
+	require.NoError(t, err)
+	assert.Equal(t, "EXISTS (SELECT 1 FROM host_labels_effective l WHERE l.host_id = h.id AND l.key = $3 AND l.value = $4)"+
+		" AND NOT EXISTS (SELECT 1 FROM host_labels_effective l WHERE l.host_id = h.id AND l.key = $5)", condition)
+	assert.Equal(t, []any{int32(50), "host1", "env", "prod", "canary"}, args)
+}
+
+func TestSelectorConditionInvalid(t *testing.T) {
+	_, _, err := grpcserver.SelectorCondition("env=pr od", "hosts.id", nil)
+	assert.Error(t, err)
+}
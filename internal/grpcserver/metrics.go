@@ -2,8 +2,11 @@ package grpcserver
 
 import (
 	"context"
+	"data_agent/internal/models"
 	"data_agent/proto"
 	"database/sql"
+	"encoding/json"
+	"fmt"
 )
 
 type MetricService struct {
@@ -11,57 +14,145 @@ type MetricService struct {
 	DB *sql.DB
 }
 
+// rows returned by list requests without a limit
+const defaultListLimit = 100
+
+// limit of a list request, zero or a negative limit selects the default
+func listLimit(limit int32) int32 {
+	if limit <= 0 {
+		return defaultListLimit
+	}
+	return limit
+}
+
+// columns of a metric row in the order scanned by scanMetrics
+const metricColumns = `m.id, m.host_id, m.uptime, m.cpu,
+	COALESCE(m.cpu_cores::text, 'null'), COALESCE(m.cpu_times::text, 'null'),
+	m.load1, m.load5, m.load15,
+	m.ram, COALESCE(m.memory::text, 'null'),
+	m.disk, COALESCE(m.disk_io::text, 'null'), m.network,
+	COALESCE(m.pressure::text, 'null'), COALESCE(m.sensors::text, 'null'),
+	COALESCE(m.sockets::text, 'null'), COALESCE(m.plugins::text, 'null'),
+	COALESCE(m.logs::text, 'null'), m.time`
+
 // retrieves all metrics from the database
 func (s *MetricService) ListMetrics(ctx context.Context, req *proto.MetricRequest) (*proto.MetricList, error) {
-	// query all metrics for a specific hostname with a limit
+	// without host fields metrics of all hosts matching the label selector are returned,
+	// a hostname shared by several hosts returns the metrics of each of them
+	hosts, args := hostCondition("h", req.HostId, req.MachineId, req.Hostname, []any{listLimit(req.Limit)})
+	condition, args, err := selectorCondition(req.Selector, "m.host_id", args)
+	if err != nil {
+		return nil, err
+	}
+
+	// query metrics of the selected hosts with a limit
 	query := `
-		SELECT m.id, m.host_id, m.uptime, m.cpu, m.ram, m.disk, m.network, m.time
+		SELECT ` + metricColumns + `
 		FROM metrics m
 		JOIN hosts h ON m.host_id = h.id
-		WHERE h.hostname = $1
+		WHERE ` + hosts + ` AND ` + condition + `
 		ORDER BY m.time DESC
-		LIMIT $2
+		LIMIT $1
 	`
-	rows, err := s.DB.Query(query, req.Hostname, req.Limit)
+	rows, err := s.DB.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var metrics []*proto.Metric
-	for rows.Next() {
-		var metric proto.Metric
-		if err := rows.Scan(&metric.Id, &metric.HostId, &metric.Uptime, &metric.Cpu, &metric.Ram, &metric.Disk, &metric.Network, &metric.Time); err != nil {
-			return nil, err
-		}
-		metrics = append(metrics, &metric)
+	metrics, err := scanMetrics(rows)
+	if err != nil {
+		return nil, err
 	}
-
 	return &proto.MetricList{Metrics: metrics}, nil
 }
 
 // retrieves the latest metrics
-func (s *MetricService) GetLatestMetrics(ctx context.Context, _ *proto.Empty) (*proto.MetricList, error) {
-	// query latest metrics for all hosts
+func (s *MetricService) GetLatestMetrics(ctx context.Context, req *proto.HostFilter) (*proto.MetricList, error) {
+	condition, args, err := selectorCondition(req.Selector, "m.host_id", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// query latest metrics for all hosts matching the label selector
 	query := `
-		SELECT DISTINCT ON (m.host_id) m.id, m.host_id, m.uptime, m.cpu, m.ram, m.disk, m.network, m.time
+		SELECT DISTINCT ON (m.host_id) ` + metricColumns + `
 		FROM metrics m
+		WHERE ` + condition + `
 		ORDER BY m.host_id, m.time DESC
 	`
-	rows, err := s.DB.Query(query)
+	rows, err := s.DB.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
+	metrics, err := scanMetrics(rows)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.MetricList{Metrics: metrics}, nil
+}
+
+// scan metric rows selected with metricColumns
+func scanMetrics(rows *sql.Rows) ([]*proto.Metric, error) {
 	var metrics []*proto.Metric
 	for rows.Next() {
 		var metric proto.Metric
-		if err := rows.Scan(&metric.Id, &metric.HostId, &metric.Uptime, &metric.Cpu, &metric.Ram, &metric.Disk, &metric.Network, &metric.Time); err != nil {
+		var diskIO string
+		if err := rows.Scan(
+			&metric.Id, &metric.HostId, &metric.Uptime, &metric.Cpu,
+			&metric.CpuCores, &metric.CpuTimes,
+			&metric.Load1, &metric.Load5, &metric.Load15,
+			&metric.Ram, &metric.Memory,
+			&metric.Disk, &diskIO, &metric.Network,
+			&metric.Pressure, &metric.Sensors, &metric.Sockets, &metric.Plugins, &metric.Logs, &metric.Time,
+		); err != nil {
+			return nil, err
+		}
+
+		var err error
+		if metric.DiskIo, err = toProtoDiskIO(diskIO); err != nil {
 			return nil, err
 		}
 		metrics = append(metrics, &metric)
 	}
+	return metrics, rows.Err()
+}
 
-	return &proto.MetricList{Metrics: metrics}, nil
+// convert stored block device metrics to proto messages
+func toProtoDiskIO(raw string) ([]*proto.DiskIO, error) {
+	var devices []models.DiskIOMetric
+	if err := json.Unmarshal([]byte(raw), &devices); err != nil {
+		return nil, fmt.Errorf("decode disk io metrics: %w", err)
+	}
+
+	result := make([]*proto.DiskIO, 0, len(devices))
+	for _, d := range devices {
+		msg := &proto.DiskIO{
+			Device:       d.Device,
+			Kind:         d.Kind,
+			ReadCount:    d.ReadCount,
+			WriteCount:   d.WriteCount,
+			ReadBytes:    d.ReadBytes,
+			WriteBytes:   d.WriteBytes,
+			ReadTimeMs:   d.ReadTimeMs,
+			WriteTimeMs:  d.WriteTimeMs,
+			IoTimeMs:     d.IOTimeMs,
+			WeightedIoMs: d.WeightedIOMs,
+			InProgress:   d.InProgress,
+		}
+		if r := d.Rates; r != nil {
+			msg.ReadOpsPerSec = r.ReadOpsPerSec
+			msg.WriteOpsPerSec = r.WriteOpsPerSec
+			msg.ReadBytesPerSec = r.ReadBytesPerSec
+			msg.WriteBytesPerSec = r.WriteBytesPerSec
+			msg.UtilPercent = r.UtilPercent
+			msg.AvgReadLatencyMs = r.AvgReadLatency
+			msg.AvgWriteLatencyMs = r.AvgWriteLatency
+			msg.AvgQueueSize = r.AvgQueueSize
+		}
+		result = append(result, msg)
+	}
+	return result, nil
 }
@@ -0,0 +1,39 @@
+package grpcserver
+
+import (
+	"context"
+	"data_agent/proto"
+)
+
+// retrieves probe results, newest first, optionally for selected hosts, one probe or failures only
+func (s *MetricService) ListProbeResults(ctx context.Context, req *proto.ProbeRequest) (*proto.ProbeResultList, error) {
+	hosts, args := hostCondition("h", req.HostId, req.MachineId, req.Hostname, []any{req.Name, req.FailedOnly, listLimit(req.Limit)})
+	rows, err := s.DB.Query(`
+		SELECT h.id, h.hostname, p.name, p.type, p.target, p.success, COALESCE(p.latency_ms, 0),
+			COALESCE(p.status_code, 0), COALESCE(p.error, ''), p.time
+		FROM probe_results p
+		JOIN hosts h ON p.host_id = h.id
+		WHERE `+hosts+`
+			AND ($1::text = '' OR p.name = $1)
+			AND (NOT $2 OR NOT p.success)
+		ORDER BY p.time DESC
+		LIMIT $3
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := &proto.ProbeResultList{}
+	for rows.Next() {
+		var result proto.ProbeResult
+		if err := rows.Scan(
+			&result.HostId, &result.Hostname, &result.Name, &result.Type, &result.Target, &result.Success, &result.LatencyMs,
+			&result.StatusCode, &result.Error, &result.Time,
+		); err != nil {
+			return nil, err
+		}
+		list.Results = append(list.Results, &result)
+	}
+	return list, rows.Err()
+}
@@ -0,0 +1,59 @@
+package grpcserver
+
+import (
+	"context"
+	"data_agent/internal/models"
+	"data_agent/proto"
+	"encoding/json"
+	"fmt"
+)
+
+// retrieves the latest top process table of a host
+func (s *MetricService) ListProcesses(ctx context.Context, req *proto.HostName) (*proto.ProcessList, error) {
+	hostID, err := resolveHost(ctx, s.DB, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// query the newest sample that carries processes
+	var raw, sampleTime string
+	err = s.DB.QueryRow(`
+		SELECT m.processes::text, m.time
+		FROM metrics m
+		WHERE m.host_id = $1 AND m.processes IS NOT NULL AND m.processes <> 'null'::jsonb
+		ORDER BY m.time DESC
+		LIMIT 1
+	`, hostID).Scan(&raw, &sampleTime)
+	if err != nil {
+		return nil, err
+	}
+
+	var table models.ProcessTable
+	if err := json.Unmarshal([]byte(raw), &table); err != nil {
+		return nil, fmt.Errorf("decode process table: %w", err)
+	}
+
+	return &proto.ProcessList{
+		ByCpu: toProtoProcesses(table.ByCPU),
+		ByRss: toProtoProcesses(table.ByRSS),
+		Time:  sampleTime,
+	}, nil
+}
+
+// convert stored processes to proto messages
+func toProtoProcesses(procs []models.ProcessMetric) []*proto.Process {
+	result := make([]*proto.Process, 0, len(procs))
+	for _, p := range procs {
+		result = append(result, &proto.Process{
+			Pid:        p.PID,
+			Name:       p.Name,
+			User:       p.User,
+			Cmdline:    p.Cmdline,
+			CpuPercent: p.CPUPercent,
+			Rss:        p.RSS,
+			Threads:    p.Threads,
+			OpenFds:    p.OpenFDs,
+		})
+	}
+	return result
+}
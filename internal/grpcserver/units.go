@@ -0,0 +1,59 @@
+package grpcserver
+
+import (
+	"context"
+	"data_agent/proto"
+	"database/sql"
+	"time"
+)
+
+// samples without units for this long mean the host follows no units anymore,
+// shorter gaps are samples in which the systemd collector failed
+const unitsStaleAfter = 10 * time.Minute
+
+// retrieves units that are failed or waiting to be restarted in the latest sample with units of every host
+func (s *MetricService) ListFailedUnits(ctx context.Context, req *proto.UnitFilter) (*proto.SystemdUnitList, error) {
+	condition, args, err := selectorCondition(req.Selector, "u.host_id", []any{req.Unit, unitsStaleAfter.Seconds()})
+	if err != nil {
+		return nil, err
+	}
+
+	// only units of the latest sample with units of a host are reported, units missing from it
+	// were removed from the agent settings
+	rows, err := s.DB.Query(`
+		WITH latest AS (
+			SELECT DISTINCT ON (host_id) host_id, metric_id, time
+			FROM systemd_units
+			ORDER BY host_id, time DESC
+		)
+		SELECT h.id, h.hostname, u.unit, u.load_state, u.active_state, u.sub_state, u.restarts, u.state_change, u.time
+		FROM systemd_units u
+		JOIN latest l ON u.metric_id = l.metric_id
+		JOIN hosts h ON u.host_id = h.id
+		WHERE (u.active_state = 'failed' OR u.sub_state = 'auto-restart')
+			AND ($1::text = '' OR u.unit = $1) AND `+condition+`
+			AND l.time >= (SELECT max(m.time) FROM metrics m WHERE m.host_id = l.host_id) - $2::float8 * interval '1 second'
+		ORDER BY h.hostname, u.unit
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := &proto.SystemdUnitList{}
+	for rows.Next() {
+		var unit proto.SystemdUnit
+		var stateChange sql.NullTime
+		if err := rows.Scan(
+			&unit.HostId, &unit.Hostname, &unit.Unit, &unit.LoadState, &unit.ActiveState, &unit.SubState, &unit.Restarts,
+			&stateChange, &unit.Time,
+		); err != nil {
+			return nil, err
+		}
+		if stateChange.Valid {
+			unit.StateChange = stateChange.Time.Format(time.RFC3339)
+		}
+		list.Units = append(list.Units, &unit)
+	}
+	return list, rows.Err()
+}
@@ -1,13 +1,40 @@
 package models
 
+import "time"
+
 // represents a monitored host system
 type Host struct {
 	ID          int64  `json:"id"`
+	MachineID   string `json:"machine_id,omitempty"`
 	Hostname    string `json:"hostname"`
 	OS          string `json:"os"`
 	Platform    string `json:"platform"`
 	PlatformVer string `json:"platformver"`
 	KernelVer   string `json:"kernelver"`
+	// static labels configured on the agent
+	Labels map[string]string `json:"labels,omitempty"`
+	// sent on startup and when it changes, nil otherwise
+	Inventory *HostInventory `json:"inventory,omitempty"`
+}
+
+// hardware and platform details of a host
+type HostInventory struct {
+	CPUModel           string         `json:"cpu_model"`
+	CPUCores           int            `json:"cpu_cores"`
+	CPUThreads         int            `json:"cpu_threads"`
+	MemoryTotal        uint64         `json:"memory_total"`
+	Interfaces         []NetInterface `json:"interfaces"`
+	BootTime           time.Time      `json:"boot_time"`
+	Virtualization     string         `json:"virtualization"`
+	VirtualizationRole string         `json:"virtualization_role"`
+	Arch               string         `json:"arch"`
+}
+
+// network interface with its hardware and IP addresses in CIDR notation
+type NetInterface struct {
+	Name  string   `json:"name"`
+	MAC   string   `json:"mac"`
+	Addrs []string `json:"addrs"`
 }
 
 // constructor for Host with basic validation
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// sources of host labels, labels set through the API override agent ones with the same key
+const (
+	LabelSourceAgent = "agent"
+	LabelSourceAPI   = "api"
+)
+
+var (
+	labelKeyPattern   = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.\-/]{0,62}$`)
+	labelValuePattern = regexp.MustCompile(`^[A-Za-z0-9_.\-/:]{0,255}$`)
+)
+
+// validation of label keys and values
+func ValidateLabels(labels map[string]string) error {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if !labelKeyPattern.MatchString(key) {
+			return fmt.Errorf("invalid label key %q: expected up to 63 letters, digits, '_', '.', '-' or '/'", key)
+		}
+		if !labelValuePattern.MatchString(labels[key]) {
+			return fmt.Errorf("invalid value of label %q: expected up to 255 letters, digits, '_', '.', '-', '/' or ':'", key)
+		}
+	}
+	return nil
+}
+
+// operators of a label selector requirement
+const (
+	SelectorEquals    = "="
+	SelectorNotEquals = "!="
+	SelectorExists    = "exists"
+	SelectorNotExists = "!exists"
+)
+
+// single condition of a label selector
+type LabelRequirement struct {
+	Key      string
+	Operator string
+	Value    string
+}
+
+// parse a comma-separated selector such as "env=prod,role!=db,team,!canary"
+func ParseSelector(selector string) ([]LabelRequirement, error) {
+	var reqs []LabelRequirement
+	for _, item := range strings.Split(selector, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		var req LabelRequirement
+		switch {
+		case strings.Contains(item, "!="):
+			key, value, _ := strings.Cut(item, "!=")
+			req = LabelRequirement{Key: strings.TrimSpace(key), Operator: SelectorNotEquals, Value: strings.TrimSpace(value)}
+		case strings.Contains(item, "="):
+			key, value, _ := strings.Cut(strings.Replace(item, "==", "=", 1), "=")
+			req = LabelRequirement{Key: strings.TrimSpace(key), Operator: SelectorEquals, Value: strings.TrimSpace(value)}
+		case strings.HasPrefix(item, "!"):
+			req = LabelRequirement{Key: strings.TrimSpace(item[1:]), Operator: SelectorNotExists}
+		default:
+			req = LabelRequirement{Key: item, Operator: SelectorExists}
+		}
+
+		if err := ValidateLabels(map[string]string{req.Key: req.Value}); err != nil {
+			return nil, fmt.Errorf("invalid selector %q: %w", item, err)
+		}
+		reqs = append(reqs, req)
+	}
+	return reqs, nil
+}
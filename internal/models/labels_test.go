@@ -0,0 +1,35 @@
+package models_test
+
+import (
+	"testing"
+
+	"data_agent/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSelector(t *testing.T) {
+	reqs, err := models.ParseSelector(" env=prod, role!=db ,tier==web,team,!canary,")
+	require.NoError(t, err)
+	assert.Equal(t, []models.LabelRequirement{
+		{Key: "env", Operator: models.SelectorEquals, Value: "prod"},
+		{Key: "role", Operator: models.SelectorNotEquals, Value: "db"},
+		{Key: "tier", Operator: models.SelectorEquals, Value: "web"},
+		{Key: "team", Operator: models.SelectorExists},
+		{Key: "canary", Operator: models.SelectorNotExists},
+	}, reqs)
+}
+
+func TestParseSelectorEmpty(t *testing.T) {
+	reqs, err := models.ParseSelector("")
+	require.NoError(t, err)
+	assert.Empty(t, reqs)
+}
+
+func TestParseSelectorInvalid(t *testing.T) {
+	for _, selector := range []string{"env=prod,=db", "!", "env=pr od", "env=a=b"} {
+		_, err := models.ParseSelector(selector)
+		assert.Error(t, err, selector)
+	}
+}
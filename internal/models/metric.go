@@ -2,20 +2,42 @@ package models
 
 import (
 	"errors"
+	"fmt"
 	"log"
 	"time"
 )
 
 // base metrics for all systems
 type Metric struct {
-	ID      int64        `json:"id"`
-	HostID  int64        `json:"host_id"`
-	Uptime  uint64       `json:"uptime"`
-	CPU     float64      `json:"cpu"`
-	RAM     float64      `json:"ram"`
-	Disk    []DiskMetric `json:"disk,omitempty"`
-	Network []NetMetric  `json:"network,omitempty"`
-	Time    time.Time    `json:"time"`
+	ID           int64            `json:"id"`
+	HostID       int64            `json:"host_id"`
+	Uptime       uint64           `json:"uptime"`
+	CPU          *float64         `json:"cpu,omitempty"`
+	CPUCores     []float64        `json:"cpu_cores,omitempty"`
+	CPUTimes     *CPUTimes        `json:"cpu_times,omitempty"`
+	Load         *LoadAvg         `json:"load,omitempty"`
+	RAM          float64          `json:"ram"`
+	Memory       *MemoryMetric    `json:"memory,omitempty"`
+	Disk         []DiskMetric     `json:"disk,omitempty"`
+	DiskIO       []DiskIOMetric   `json:"disk_io,omitempty"`
+	Network      []NetMetric      `json:"network,omitempty"`
+	Processes    *ProcessTable    `json:"processes,omitempty"`
+	Pressure     *PressureMetric  `json:"pressure,omitempty"`
+	Cgroups      []CgroupMetric   `json:"cgroups,omitempty"`
+	Sensors      *SensorMetric    `json:"sensors,omitempty"`
+	Sockets      *SocketMetric    `json:"sockets,omitempty"`
+	Plugins      []PluginResult   `json:"plugins,omitempty"`
+	Probes       []ProbeResult    `json:"probes,omitempty"`
+	Logs         []LogMetric      `json:"logs,omitempty"`
+	SystemdUnits []SystemdUnit    `json:"systemd_units,omitempty"`
+	Errors       []CollectorError `json:"errors,omitempty"`
+	Time         time.Time        `json:"time"`
+}
+
+// collector that failed while building a sample
+type CollectorError struct {
+	Collector string `json:"collector"`
+	Error     string `json:"error"`
 }
 
 // constructor for Metric
@@ -23,7 +45,7 @@ func NewMetric(uptime uint64, cpu, ram float64, disk []DiskMetric, network []Net
 
 	metric := &Metric{
 		Uptime:  uptime,
-		CPU:     cpu,
+		CPU:     &cpu,
 		RAM:     ram,
 		Disk:    disk,
 		Network: network,
@@ -39,36 +61,323 @@ func NewMetric(uptime uint64, cpu, ram float64, disk []DiskMetric, network []Net
 
 // validation metrics
 func (m *Metric) Validate() error {
-	if m.Uptime == 0 {
-		return errors.New("invalid uptime value")
-	}
-	if m.CPU < 0 || m.CPU > 100 {
+	// uptime may be zero when its collector is disabled or failed
+	if m.CPU != nil && (*m.CPU < 0 || *m.CPU > 100) {
 		return errors.New("invalid cpu value")
 	}
+	for _, core := range m.CPUCores {
+		if core < 0 || core > 100 {
+			return errors.New("invalid cpu core value")
+		}
+	}
+	if m.Memory != nil {
+		if err := m.Memory.Validate(); err != nil {
+			return err
+		}
+	}
+	for i := range m.Disk {
+		if err := m.Disk[i].Validate(); err != nil {
+			return err
+		}
+	}
 	if m.RAM < 0 || m.RAM > 100 {
 		return errors.New("invalid ram value")
 	}
 	return nil
 }
 
-// metrics for disk usage
+// share of CPU time spent in each state in percent
+type CPUTimes struct {
+	User    float64 `json:"user"`
+	System  float64 `json:"system"`
+	Idle    float64 `json:"idle"`
+	Nice    float64 `json:"nice"`
+	IOWait  float64 `json:"iowait"`
+	IRQ     float64 `json:"irq"`
+	SoftIRQ float64 `json:"softirq"`
+	Steal   float64 `json:"steal"`
+}
+
+// system load averages over 1, 5 and 15 minutes
+type LoadAvg struct {
+	Load1  float64 `json:"load1"`
+	Load5  float64 `json:"load5"`
+	Load15 float64 `json:"load15"`
+}
+
+// memory and swap usage in bytes, swap in and out are cumulative since boot
+type MemoryMetric struct {
+	Total           uint64  `json:"total"`
+	Available       uint64  `json:"available"`
+	Used            uint64  `json:"used"`
+	Free            uint64  `json:"free"`
+	Cached          uint64  `json:"cached"`
+	Buffers         uint64  `json:"buffers"`
+	Shared          uint64  `json:"shared"`
+	Dirty           uint64  `json:"dirty"`
+	Slab            uint64  `json:"slab"`
+	SwapTotal       uint64  `json:"swap_total"`
+	SwapUsed        uint64  `json:"swap_used"`
+	SwapFree        uint64  `json:"swap_free"`
+	SwapUsedPercent float64 `json:"swap_used_percent"`
+	SwapIn          uint64  `json:"swap_in"`
+	SwapOut         uint64  `json:"swap_out"`
+}
+
+// validation memory metrics
+func (m *MemoryMetric) Validate() error {
+	if m.SwapUsedPercent < 0 || m.SwapUsedPercent > 100 {
+		return errors.New("invalid swap value")
+	}
+	return nil
+}
+
+// metrics for disk usage, inode counts are zero on filesystems without fixed inode tables
 type DiskMetric struct {
-	Path        string  `json:"path"`
-	Total       uint64  `json:"total"`
-	Used        uint64  `json:"used"`
-	Free        uint64  `json:"free"`
-	UsedPercent float64 `json:"used_percent"`
+	Path              string  `json:"path"`
+	Total             uint64  `json:"total"`
+	Used              uint64  `json:"used"`
+	Free              uint64  `json:"free"`
+	UsedPercent       float64 `json:"used_percent"`
+	InodesTotal       uint64  `json:"inodes_total"`
+	InodesUsed        uint64  `json:"inodes_used"`
+	InodesFree        uint64  `json:"inodes_free"`
+	InodesUsedPercent float64 `json:"inodes_used_percent"`
+}
+
+// validation disk metrics
+func (d *DiskMetric) Validate() error {
+	if d.UsedPercent < 0 || d.UsedPercent > 100 {
+		return fmt.Errorf("invalid disk used percent for %s", d.Path)
+	}
+	if d.InodesUsedPercent < 0 || d.InodesUsedPercent > 100 {
+		return fmt.Errorf("invalid inode used percent for %s", d.Path)
+	}
+	return nil
+}
+
+// I/O counters of a block device, times in milliseconds
+type DiskIOMetric struct {
+	Device       string       `json:"device"`
+	Kind         string       `json:"kind,omitempty"`
+	ReadCount    uint64       `json:"read_count"`
+	WriteCount   uint64       `json:"write_count"`
+	ReadBytes    uint64       `json:"read_bytes"`
+	WriteBytes   uint64       `json:"write_bytes"`
+	ReadTimeMs   uint64       `json:"read_time_ms"`
+	WriteTimeMs  uint64       `json:"write_time_ms"`
+	IOTimeMs     uint64       `json:"io_time_ms"`
+	WeightedIOMs uint64       `json:"weighted_io_ms"`
+	InProgress   uint64       `json:"in_progress"`
+	Rates        *DiskIORates `json:"rates,omitempty"`
+}
+
+// kinds of block devices, only disks are summed to get the I/O of a host
+const (
+	BlockDisk      = "disk"
+	BlockPartition = "partition"
+	// device mapper and md devices, their I/O is also counted on the devices below them
+	BlockStacked = "stacked"
+)
+
+// block device rates and latencies since the previous sample
+type DiskIORates struct {
+	ReadOpsPerSec    float64 `json:"read_ops_per_sec"`
+	WriteOpsPerSec   float64 `json:"write_ops_per_sec"`
+	ReadBytesPerSec  float64 `json:"read_bytes_per_sec"`
+	WriteBytesPerSec float64 `json:"write_bytes_per_sec"`
+	UtilPercent      float64 `json:"util_percent"`
+	AvgReadLatency   float64 `json:"avg_read_latency_ms"`
+	AvgWriteLatency  float64 `json:"avg_write_latency_ms"`
+	AvgQueueSize     float64 `json:"avg_queue_size"`
 }
 
 // metrics for network usage
 type NetMetric struct {
-	Name        string `json:"name"`
-	BytesSent   uint64 `json:"bytes_sent"`
-	BytesRecv   uint64 `json:"bytes_recv"`
-	PacketsSent uint64 `json:"packets_sent"`
-	PacketsRecv uint64 `json:"packets_recv"`
-	ErrIn       uint64 `json:"err_in"`
-	ErrOut      uint64 `json:"err_out"`
-	DropIn      uint64 `json:"drop_in"`
-	DropOut     uint64 `json:"drop_out"`
+	Name        string    `json:"name"`
+	BytesSent   uint64    `json:"bytes_sent"`
+	BytesRecv   uint64    `json:"bytes_recv"`
+	PacketsSent uint64    `json:"packets_sent"`
+	PacketsRecv uint64    `json:"packets_recv"`
+	ErrIn       uint64    `json:"err_in"`
+	ErrOut      uint64    `json:"err_out"`
+	DropIn      uint64    `json:"drop_in"`
+	DropOut     uint64    `json:"drop_out"`
+	Rates       *NetRates `json:"rates,omitempty"`
+}
+
+// per-second network rates since the previous sample
+type NetRates struct {
+	BytesSentPerSec   float64 `json:"bytes_sent_per_sec"`
+	BytesRecvPerSec   float64 `json:"bytes_recv_per_sec"`
+	PacketsSentPerSec float64 `json:"packets_sent_per_sec"`
+	PacketsRecvPerSec float64 `json:"packets_recv_per_sec"`
+	ErrInPerSec       float64 `json:"err_in_per_sec"`
+	ErrOutPerSec      float64 `json:"err_out_per_sec"`
+	DropInPerSec      float64 `json:"drop_in_per_sec"`
+	DropOutPerSec     float64 `json:"drop_out_per_sec"`
+}
+
+// processes using the most CPU and the most memory
+type ProcessTable struct {
+	ByCPU []ProcessMetric `json:"by_cpu"`
+	ByRSS []ProcessMetric `json:"by_rss"`
+}
+
+// resource usage of a single process, cpu percent is relative to one core
+type ProcessMetric struct {
+	PID        int32   `json:"pid"`
+	Name       string  `json:"name"`
+	User       string  `json:"user"`
+	Cmdline    string  `json:"cmdline"`
+	CPUPercent float64 `json:"cpu_percent"`
+	RSS        uint64  `json:"rss"`
+	Threads    int32   `json:"threads"`
+	OpenFDs    int32   `json:"open_fds"`
+}
+
+// Pressure Stall Information per resource, nil when the kernel does not provide it
+type PressureMetric struct {
+	CPU    *PressureStat `json:"cpu,omitempty"`
+	Memory *PressureStat `json:"memory,omitempty"`
+	IO     *PressureStat `json:"io,omitempty"`
+}
+
+// share of time some or all tasks were stalled on a resource
+type PressureStat struct {
+	Some *PressureLine `json:"some,omitempty"`
+	Full *PressureLine `json:"full,omitempty"`
+}
+
+// stall averages in percent and total stall time in microseconds
+type PressureLine struct {
+	Avg10  float64 `json:"avg10"`
+	Avg60  float64 `json:"avg60"`
+	Avg300 float64 `json:"avg300"`
+	Total  uint64  `json:"total"`
+}
+
+// resource usage of a cgroup v2 group, memory max is zero without a limit
+type CgroupMetric struct {
+	Path          string  `json:"path"`
+	ContainerID   string  `json:"container_id,omitempty"`
+	CPUUsageUsec  uint64  `json:"cpu_usage_usec"`
+	CPUUserUsec   uint64  `json:"cpu_user_usec"`
+	CPUSystemUsec uint64  `json:"cpu_system_usec"`
+	CPUPercent    float64 `json:"cpu_percent"`
+	NrPeriods     uint64  `json:"nr_periods"`
+	NrThrottled   uint64  `json:"nr_throttled"`
+	ThrottledUsec uint64  `json:"throttled_usec"`
+	MemoryCurrent uint64  `json:"memory_current"`
+	MemoryMax     uint64  `json:"memory_max"`
+	IOReadBytes   uint64  `json:"io_read_bytes"`
+	IOWriteBytes  uint64  `json:"io_write_bytes"`
+	IOReadOps     uint64  `json:"io_read_ops"`
+	IOWriteOps    uint64  `json:"io_write_ops"`
+}
+
+// hardware temperature and fan sensors
+type SensorMetric struct {
+	Temperatures []TemperatureSensor `json:"temperatures,omitempty"`
+	Fans         []FanSensor         `json:"fans,omitempty"`
+}
+
+// temperature in degrees Celsius, high and critical are zero when the sensor has no such threshold
+type TemperatureSensor struct {
+	Name     string  `json:"name"`
+	Current  float64 `json:"current"`
+	High     float64 `json:"high,omitempty"`
+	Critical float64 `json:"critical,omitempty"`
+}
+
+// fan speed and its alarm threshold in RPM
+type FanSensor struct {
+	Name string `json:"name"`
+	RPM  int64  `json:"rpm"`
+	Min  int64  `json:"min,omitempty"`
+}
+
+// TCP connection states and socket counters, delta is nil on the first sample and after a counter reset
+type SocketMetric struct {
+	TCPStates map[string]int  `json:"tcp_states"`
+	Counters  SocketCounters  `json:"counters"`
+	Delta     *SocketCounters `json:"delta,omitempty"`
+}
+
+// TCP and UDP counters, cumulative since boot or grown since the previous sample
+type SocketCounters struct {
+	TCPActiveOpens     uint64 `json:"tcp_active_opens"`
+	TCPPassiveOpens    uint64 `json:"tcp_passive_opens"`
+	TCPAttemptFails    uint64 `json:"tcp_attempt_fails"`
+	TCPEstabResets     uint64 `json:"tcp_estab_resets"`
+	TCPOutSegs         uint64 `json:"tcp_out_segs"`
+	TCPRetransSegs     uint64 `json:"tcp_retrans_segs"`
+	TCPInErrs          uint64 `json:"tcp_in_errs"`
+	TCPOutRsts         uint64 `json:"tcp_out_rsts"`
+	TCPListenOverflows uint64 `json:"tcp_listen_overflows"`
+	TCPListenDrops     uint64 `json:"tcp_listen_drops"`
+	TCPTimeouts        uint64 `json:"tcp_timeouts"`
+	UDPInDatagrams     uint64 `json:"udp_in_datagrams"`
+	UDPOutDatagrams    uint64 `json:"udp_out_datagrams"`
+	UDPNoPorts         uint64 `json:"udp_no_ports"`
+	UDPInErrors        uint64 `json:"udp_in_errors"`
+	UDPRcvbufErrors    uint64 `json:"udp_rcvbuf_errors"`
+	UDPSndbufErrors    uint64 `json:"udp_sndbuf_errors"`
+}
+
+// outcome of an exec plugin run
+type PluginResult struct {
+	Name       string         `json:"name"`
+	Status     string         `json:"status"`
+	Message    string         `json:"message,omitempty"`
+	Metrics    []CustomMetric `json:"metrics,omitempty"`
+	Error      string         `json:"error,omitempty"`
+	DurationMs float64        `json:"duration_ms"`
+	Time       time.Time      `json:"time"`
+}
+
+// named value reported by a plugin, thresholds come from Nagios perfdata
+type CustomMetric struct {
+	Name  string   `json:"name"`
+	Value float64  `json:"value"`
+	Unit  string   `json:"unit,omitempty"`
+	Warn  *float64 `json:"warn,omitempty"`
+	Crit  *float64 `json:"crit,omitempty"`
+}
+
+// outcome of a synthetic HTTP, TCP or DNS check
+type ProbeResult struct {
+	Name       string    `json:"name"`
+	Type       string    `json:"type"`
+	Target     string    `json:"target"`
+	Success    bool      `json:"success"`
+	LatencyMs  float64   `json:"latency_ms"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	Time       time.Time `json:"time"`
+}
+
+// lines appended to a followed log file since the previous sample
+type LogMetric struct {
+	Path  string `json:"path"`
+	Lines uint64 `json:"lines"`
+	// matching lines by pattern name
+	Matches map[string]uint64 `json:"matches"`
+	Samples []LogSample       `json:"samples,omitempty"`
+}
+
+// matching line forwarded as an example
+type LogSample struct {
+	Pattern string `json:"pattern"`
+	Line    string `json:"line"`
+}
+
+// state of a systemd unit, state change is nil when the unit did not change state since boot
+type SystemdUnit struct {
+	Name        string     `json:"name"`
+	LoadState   string     `json:"load_state"`
+	ActiveState string     `json:"active_state"`
+	SubState    string     `json:"sub_state"`
+	Restarts    uint32     `json:"restarts"`
+	StateChange *time.Time `json:"state_change,omitempty"`
 }
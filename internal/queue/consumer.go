@@ -99,6 +99,9 @@ func (c *Consumer) ConsumeMetrics() error {
 			// acknowledge message
 			d.Ack(false)
 			log.Printf("Metric saved from queue: host=%s", metric.Host.Hostname)
+			for _, e := range metric.Metric.Errors {
+				log.Printf("Partial metric from host=%s: collector %s failed: %s", metric.Host.Hostname, e.Collector, e.Error)
+			}
 		}
 	}
 }
@@ -0,0 +1,10 @@
+package queue
+
+import "github.com/streadway/amqp"
+
+// switch the publisher to a channel without dialing a broker
+func (p *Publisher) Attach(ch Channel, confirms <-chan amqp.Confirmation) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.attach(ch, amqp.Queue{Name: "metrics"}, confirms)
+}
@@ -4,21 +4,52 @@ import (
 	"context"
 	"data_agent/internal/models"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/streadway/amqp"
 )
 
+// default number of messages awaiting broker confirmation
+const defaultWindow = 256
+
+var errWindowFull = errors.New("too many unconfirmed messages")
+
+// channel operations used by the publisher, implemented by *amqp.Channel
+type Channel interface {
+	Publish(exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error
+	Close() error
+}
+
 type Publisher struct {
 	Conn   *amqp.Connection
-	Ch     *amqp.Channel
+	Ch     Channel
 	Q      amqp.Queue
 	Ctx    context.Context
+	Spool  *Spool
+	Window int
 	server string
 	mu     sync.Mutex
+
+	// confirm tracking for the current channel
+	outstanding map[uint64][]byte
+	retry       [][]byte
+	nextTag     uint64
+	channelGen  uint64
+	stats       PublisherStats
+}
+
+// delivery counters of the publisher
+type PublisherStats struct {
+	Published   uint64
+	Confirmed   uint64
+	Nacked      uint64
+	Retried     uint64
+	Outstanding int
 }
 
 // create a new publisher with context
@@ -62,46 +93,194 @@ func (p *Publisher) connect() error {
 		return fmt.Errorf("failed to declare queue: %w", err)
 	}
 
+	// broker acknowledges every message in confirm mode
+	if err := ch.Confirm(false); err != nil {
+		ch.Close()
+		conn.Close()
+		return fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
+	confirms := ch.NotifyPublish(make(chan amqp.Confirmation, p.window()+1))
+
 	p.Conn = conn
-	p.Ch = ch
-	p.Q = q
 	log.Println("Publisher connected and queue declared:", q.Name)
+	p.attach(ch, q, confirms)
 	return nil
 }
 
+// switch to a new channel in confirm mode, must be called with mutex held
+func (p *Publisher) attach(ch Channel, q amqp.Queue, confirms <-chan amqp.Confirmation) {
+	// messages unconfirmed on the old channel are sent again
+	p.requeueOutstanding()
+	p.nextTag = 1
+	p.channelGen++
+	go p.handleConfirms(p.channelGen, confirms)
+
+	p.Ch = ch
+	p.Q = q
+
+	// send unconfirmed and spooled messages before new ones
+	if err := p.retryUnconfirmed(); err != nil {
+		log.Println("Failed to retry unconfirmed metrics:", err)
+		return
+	}
+	p.drainSpool()
+}
+
 // publish a message without opening a new connection
 func (p *Publisher) Publish(metricMsg *models.MetricMessage) error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
-	if p.Conn == nil || p.Conn.IsClosed() {
-		return fmt.Errorf("cannot publish, connection is closed or nil")
-	}
-
-	if p.Ch == nil {
-		return fmt.Errorf("cannot publish, channel is closed or nil")
-	}
-
 	// marshaling metrics
 	body, err := json.Marshal(metricMsg)
 	if err != nil {
 		return fmt.Errorf("failed to marshal metric: %w", err)
 	}
 
+	if p.Ch == nil || (p.Conn != nil && p.Conn.IsClosed()) {
+		return p.spoolOrFail(body, metricMsg, fmt.Errorf("cannot publish, connection is closed or nil"))
+	}
+
+	// older unconfirmed messages go first
+	if err := p.retryUnconfirmed(); err != nil {
+		return p.spoolOrFail(body, metricMsg, fmt.Errorf("failed to retry unconfirmed metrics: %w", err))
+	}
+
+	// keep original order while older messages are still spooled
+	if p.Spool != nil && p.Spool.Pending() {
+		if err := p.Spool.Append(body); err != nil {
+			return fmt.Errorf("failed to spool metric: %w", err)
+		}
+		p.drainSpool()
+		return nil
+	}
+
 	// publish metrics
-	err = p.Ch.Publish("", p.Q.Name, false, false, amqp.Publishing{
+	if err := p.publishBody(body); err != nil {
+		return p.spoolOrFail(body, metricMsg, fmt.Errorf("failed to publish metric: %w", err))
+	}
+
+	log.Printf("Metric sent to queue: host=%s", metricMsg.Host.Hostname)
+	return nil
+}
+
+// publish a marshaled message on the current channel and track its delivery tag
+func (p *Publisher) publishBody(body []byte) error {
+	if len(p.outstanding) >= p.window() {
+		return errWindowFull
+	}
+
+	err := p.Ch.Publish("", p.Q.Name, false, false, amqp.Publishing{
 		DeliveryMode: amqp.Persistent,
 		ContentType:  "application/json",
 		Body:         body,
 	})
 	if err != nil {
-		return fmt.Errorf("failed to publish metric: %w", err)
+		return err
 	}
 
-	log.Printf("Metric sent to queue: host=%s", metricMsg.Host.Hostname)
+	if p.outstanding == nil {
+		p.outstanding = make(map[uint64][]byte)
+	}
+	p.outstanding[p.nextTag] = body
+	p.nextTag++
+	p.stats.Published++
+	return nil
+}
+
+// record broker acks and nacks for messages of one channel
+func (p *Publisher) handleConfirms(gen uint64, confirms <-chan amqp.Confirmation) {
+	for c := range confirms {
+		p.mu.Lock()
+		// confirmations of a replaced channel are stale
+		if gen == p.channelGen {
+			if body, ok := p.outstanding[c.DeliveryTag]; ok {
+				delete(p.outstanding, c.DeliveryTag)
+				if c.Ack {
+					p.stats.Confirmed++
+				} else {
+					p.stats.Nacked++
+					p.retry = append(p.retry, body)
+					log.Printf("Metric nacked by broker, will retry: tag=%d", c.DeliveryTag)
+				}
+			}
+		}
+		p.mu.Unlock()
+	}
+}
+
+// move unconfirmed messages to the retry list in publish order
+func (p *Publisher) requeueOutstanding() {
+	if len(p.outstanding) == 0 {
+		return
+	}
+	tags := make([]uint64, 0, len(p.outstanding))
+	for tag := range p.outstanding {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i] < tags[j] })
+	for _, tag := range tags {
+		p.retry = append(p.retry, p.outstanding[tag])
+	}
+	p.outstanding = make(map[uint64][]byte)
+}
+
+// publish nacked and unconfirmed messages again
+func (p *Publisher) retryUnconfirmed() error {
+	for len(p.retry) > 0 {
+		if err := p.publishBody(p.retry[0]); err != nil {
+			return err
+		}
+		p.retry = p.retry[1:]
+		p.stats.Retried++
+	}
+	return nil
+}
+
+// maximum number of unconfirmed messages
+func (p *Publisher) window() int {
+	if p.Window <= 0 {
+		return defaultWindow
+	}
+	return p.Window
+}
+
+// return a snapshot of delivery counters
+func (p *Publisher) Stats() PublisherStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := p.stats
+	stats.Outstanding = len(p.outstanding) + len(p.retry)
+	return stats
+}
+
+// write the message to the spool when one is configured, otherwise return the cause
+func (p *Publisher) spoolOrFail(body []byte, metricMsg *models.MetricMessage, cause error) error {
+	if p.Spool == nil {
+		return cause
+	}
+	if err := p.Spool.Append(body); err != nil {
+		return fmt.Errorf("%v, failed to spool metric: %w", cause, err)
+	}
+	log.Printf("Metric spooled: host=%s: %v", metricMsg.Host.Hostname, cause)
 	return nil
 }
 
+// publish spooled messages in order, must be called with mutex held
+func (p *Publisher) drainSpool() {
+	if p.Spool == nil || p.Ch == nil {
+		return
+	}
+	sent, err := p.Spool.Drain(p.publishBody)
+	if sent > 0 {
+		log.Printf("Published %d spooled metrics", sent)
+	}
+	if err != nil && !errors.Is(err, errWindowFull) {
+		log.Println("Failed to drain spool, will retry after reconnect:", err)
+	}
+}
+
 // publish metrics
 func (p *Publisher) StartMetricsPublisher() {
 	for {
@@ -110,6 +289,8 @@ func (p *Publisher) StartMetricsPublisher() {
 			select {
 			case <-p.Ctx.Done():
 				log.Println("Publisher stopped by context")
+				// messages unconfirmed before the connection was lost are spooled
+				p.Close()
 				return
 			case <-time.After(5 * time.Second):
 				continue
@@ -151,5 +332,16 @@ func (p *Publisher) Close() {
 		}
 	}
 	p.Conn = nil
+
+	// keep unconfirmed messages for the next run, they are older than the spooled ones
+	p.requeueOutstanding()
+	if p.Spool != nil && len(p.retry) > 0 {
+		if err := p.Spool.Prepend(p.retry); err != nil {
+			log.Printf("Failed to spool %d unconfirmed metrics: %v", len(p.retry), err)
+		} else {
+			log.Printf("Spooled %d unconfirmed metrics", len(p.retry))
+			p.retry = nil
+		}
+	}
 	log.Println("Publisher connection closed")
 }
@@ -0,0 +1,490 @@
+package queue
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	segmentExt        = ".seg"
+	cursorFile        = "cursor"
+	recordHeaderSize  = 8
+	maxRecordSize     = 16 << 20
+	defaultSegmentMax = 4 << 20
+	defaultSpoolMax   = 64 << 20
+	defaultSpoolAge   = 24 * time.Hour
+)
+
+// limits for the on-disk spool
+type SpoolConfig struct {
+	Dir             string
+	MaxSegmentBytes int64
+	MaxTotalBytes   int64
+	MaxAge          time.Duration
+}
+
+// bounded on-disk queue of messages that could not be published
+type Spool struct {
+	cfg      SpoolConfig
+	mu       sync.Mutex
+	segments []uint64
+	sizes    map[uint64]int64
+	writer   *os.File
+	writeID  uint64
+	readID   uint64
+	readOff  int64
+	closed   bool
+}
+
+// open or create a spool in the configured directory
+func OpenSpool(cfg SpoolConfig) (*Spool, error) {
+	if cfg.Dir == "" {
+		return nil, errors.New("spool directory is not set")
+	}
+	cfg = cfg.withDefaults()
+	if err := os.MkdirAll(cfg.Dir, 0o750); err != nil {
+		return nil, fmt.Errorf("create spool directory: %w", err)
+	}
+
+	s := &Spool{cfg: cfg, sizes: make(map[uint64]int64)}
+
+	// load existing segments in order
+	entries, err := os.ReadDir(cfg.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("read spool directory: %w", err)
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, segmentExt) {
+			continue
+		}
+		id, err := strconv.ParseUint(strings.TrimSuffix(name, segmentExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			return nil, fmt.Errorf("stat spool segment: %w", err)
+		}
+		s.segments = append(s.segments, id)
+		s.sizes[id] = info.Size()
+	}
+	sort.Slice(s.segments, func(i, j int) bool { return s.segments[i] < s.segments[j] })
+
+	// restore read position
+	if err := s.loadCursor(); err != nil {
+		return nil, err
+	}
+	s.dropExpired()
+	return s, nil
+}
+
+// limits with unset values replaced by defaults
+func (cfg SpoolConfig) withDefaults() SpoolConfig {
+	if cfg.MaxSegmentBytes <= 0 {
+		cfg.MaxSegmentBytes = defaultSegmentMax
+	}
+	if cfg.MaxTotalBytes <= 0 {
+		cfg.MaxTotalBytes = defaultSpoolMax
+	}
+	if cfg.MaxTotalBytes < cfg.MaxSegmentBytes {
+		cfg.MaxSegmentBytes = cfg.MaxTotalBytes
+	}
+	if cfg.MaxAge <= 0 {
+		cfg.MaxAge = defaultSpoolAge
+	}
+	return cfg
+}
+
+// change the size and age limits of an open spool, segments over the new limits are dropped
+func (s *Spool) SetLimits(maxTotalBytes int64, maxAge time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cfg := s.cfg
+	cfg.MaxTotalBytes, cfg.MaxAge = maxTotalBytes, maxAge
+	s.cfg = cfg.withDefaults()
+	s.dropExpired()
+	s.enforceSize()
+}
+
+// append a message to the newest segment
+func (s *Spool) Append(body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return errors.New("spool is closed")
+	}
+	if len(body) > maxRecordSize {
+		return fmt.Errorf("message of %d bytes exceeds spool record limit", len(body))
+	}
+
+	return s.write(encodeRecord(body))
+}
+
+// insert messages before all spooled ones, for messages older than the spool content,
+// either all messages are written or none
+func (s *Spool) Prepend(bodies [][]byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return errors.New("spool is closed")
+	}
+	var buf bytes.Buffer
+	for _, body := range bodies {
+		if len(body) > maxRecordSize {
+			return fmt.Errorf("message of %d bytes exceeds spool record limit", len(body))
+		}
+		buf.Write(encodeRecord(body))
+	}
+	if buf.Len() == 0 {
+		return nil
+	}
+	if len(s.segments) == 0 {
+		return s.write(buf.Bytes())
+	}
+
+	// rewrite the oldest segment with the messages in front of its unread records
+	id := s.segments[0]
+	if s.writer != nil && s.writeID == id {
+		if err := s.writer.Close(); err != nil {
+			log.Println("Error closing spool segment:", err)
+		}
+		s.writer = nil
+	}
+	rest, err := os.ReadFile(s.segmentPath(id))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("read spool segment: %w", err)
+	}
+	if s.readID == id {
+		rest = rest[min(s.readOff, int64(len(rest))):]
+	}
+	buf.Write(rest)
+
+	tmp := s.segmentPath(id) + ".tmp"
+	if err := writeSynced(tmp, buf.Bytes()); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("write spool segment: %w", err)
+	}
+	if err := os.Rename(tmp, s.segmentPath(id)); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("write spool segment: %w", err)
+	}
+	s.sizes[id] = int64(buf.Len())
+	s.readID, s.readOff = id, 0
+	if err := s.saveCursor(); err != nil {
+		log.Println("Failed to save spool cursor:", err)
+	}
+	s.enforceSize()
+	return nil
+}
+
+// append encoded records to the newest segment, must be called with mutex held
+func (s *Spool) write(recs []byte) error {
+	size := int64(len(recs))
+	if s.writer == nil || s.sizes[s.writeID]+size > s.cfg.MaxSegmentBytes {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+
+	if _, err := s.writer.Write(recs); err != nil {
+		return fmt.Errorf("write spool segment: %w", err)
+	}
+	if err := s.writer.Sync(); err != nil {
+		return fmt.Errorf("sync spool segment: %w", err)
+	}
+	s.sizes[s.writeID] += size
+
+	s.dropExpired()
+	s.enforceSize()
+	return nil
+}
+
+// frame a message with its length and checksum, which detect records torn by a crash
+func encodeRecord(body []byte) []byte {
+	rec := make([]byte, recordHeaderSize+len(body))
+	binary.BigEndian.PutUint32(rec[0:4], uint32(len(body)))
+	binary.BigEndian.PutUint32(rec[4:8], crc32.ChecksumIEEE(body))
+	copy(rec[recordHeaderSize:], body)
+	return rec
+}
+
+// write a file and flush it to disk
+func writeSynced(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o640)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// send spooled messages in original order until the spool is empty or send fails
+func (s *Spool) Drain(send func(body []byte) error) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return 0, errors.New("spool is closed")
+	}
+	s.dropExpired()
+
+	// seal the active segment so new messages go to a fresh one
+	if s.writer != nil {
+		if err := s.writer.Close(); err != nil {
+			log.Println("Error closing spool segment:", err)
+		}
+		s.writer = nil
+	}
+
+	sent := 0
+	for len(s.segments) > 0 {
+		id := s.segments[0]
+		if s.readID != id {
+			s.readID, s.readOff = id, 0
+		}
+		n, err := s.drainSegment(id, send)
+		sent += n
+		if err != nil {
+			if cerr := s.saveCursor(); cerr != nil {
+				log.Println("Failed to save spool cursor:", cerr)
+			}
+			return sent, err
+		}
+		s.removeSegment(id)
+	}
+
+	s.readID, s.readOff = 0, 0
+	if err := s.saveCursor(); err != nil {
+		log.Println("Failed to save spool cursor:", err)
+	}
+	return sent, nil
+}
+
+// report whether unsent messages remain in the spool
+func (s *Spool) Pending() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, id := range s.segments {
+		if id != s.readID || s.readOff < s.sizes[id] {
+			return true
+		}
+	}
+	return false
+}
+
+// total size of spooled segments in bytes
+func (s *Spool) Size() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var total int64
+	for _, id := range s.segments {
+		total += s.sizes[id]
+	}
+	return total
+}
+
+// flush the read position and close the active segment
+func (s *Spool) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	var err error
+	if s.writer != nil {
+		err = s.writer.Close()
+		s.writer = nil
+	}
+	if cerr := s.saveCursor(); cerr != nil && err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// send records of one segment starting at the read offset
+func (s *Spool) drainSegment(id uint64, send func([]byte) error) (int, error) {
+	f, err := os.Open(s.segmentPath(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("open spool segment: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Seek(s.readOff, io.SeekStart); err != nil {
+		return 0, fmt.Errorf("seek spool segment: %w", err)
+	}
+
+	r := bufio.NewReader(f)
+	header := make([]byte, recordHeaderSize)
+	sent := 0
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			if !errors.Is(err, io.EOF) {
+				log.Printf("Spool segment %d has a truncated record, skipping the rest", id)
+			}
+			return sent, nil
+		}
+		length := binary.BigEndian.Uint32(header[0:4])
+		if length > maxRecordSize {
+			log.Printf("Spool segment %d has a corrupt record, skipping the rest", id)
+			return sent, nil
+		}
+		body := make([]byte, length)
+		if _, err := io.ReadFull(r, body); err != nil {
+			log.Printf("Spool segment %d has a truncated record, skipping the rest", id)
+			return sent, nil
+		}
+		if crc32.ChecksumIEEE(body) != binary.BigEndian.Uint32(header[4:8]) {
+			log.Printf("Spool segment %d has a corrupt record, skipping the rest", id)
+			return sent, nil
+		}
+
+		if err := send(body); err != nil {
+			return sent, err
+		}
+		s.readOff += int64(recordHeaderSize) + int64(length)
+		sent++
+	}
+}
+
+// start a new segment after the newest one
+func (s *Spool) rotate() error {
+	if s.writer != nil {
+		if err := s.writer.Close(); err != nil {
+			log.Println("Error closing spool segment:", err)
+		}
+		s.writer = nil
+	}
+
+	id := uint64(1)
+	if n := len(s.segments); n > 0 {
+		id = s.segments[n-1] + 1
+	}
+	f, err := os.OpenFile(s.segmentPath(id), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return fmt.Errorf("create spool segment: %w", err)
+	}
+
+	s.writer = f
+	s.writeID = id
+	s.segments = append(s.segments, id)
+	s.sizes[id] = 0
+	return nil
+}
+
+// drop whole segments whose newest record is older than the age limit
+func (s *Spool) dropExpired() {
+	cutoff := time.Now().Add(-s.cfg.MaxAge)
+	for len(s.segments) > 0 {
+		id := s.segments[0]
+		if s.writer != nil && id == s.writeID {
+			return
+		}
+		info, err := os.Stat(s.segmentPath(id))
+		if err == nil && info.ModTime().After(cutoff) {
+			return
+		}
+		log.Printf("Dropping expired spool segment %d", id)
+		s.removeSegment(id)
+	}
+}
+
+// drop the oldest segments while the spool exceeds its size limit
+func (s *Spool) enforceSize() {
+	var total int64
+	for _, id := range s.segments {
+		total += s.sizes[id]
+	}
+	for total > s.cfg.MaxTotalBytes && len(s.segments) > 1 {
+		id := s.segments[0]
+		total -= s.sizes[id]
+		log.Printf("Spool is over %d bytes, dropping oldest segment %d", s.cfg.MaxTotalBytes, id)
+		s.removeSegment(id)
+	}
+}
+
+// delete a segment file and forget it
+func (s *Spool) removeSegment(id uint64) {
+	if err := os.Remove(s.segmentPath(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Println("Failed to remove spool segment:", err)
+	}
+	for i, seg := range s.segments {
+		if seg == id {
+			s.segments = append(s.segments[:i], s.segments[i+1:]...)
+			break
+		}
+	}
+	delete(s.sizes, id)
+	if s.readID == id {
+		s.readID, s.readOff = 0, 0
+	}
+}
+
+// read the persisted read position
+func (s *Spool) loadCursor() error {
+	data, err := os.ReadFile(filepath.Join(s.cfg.Dir, cursorFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read spool cursor: %w", err)
+	}
+
+	var id uint64
+	var off int64
+	if _, err := fmt.Sscanf(string(data), "%d %d", &id, &off); err != nil {
+		log.Println("Ignoring invalid spool cursor:", err)
+		return nil
+	}
+	if _, ok := s.sizes[id]; ok {
+		s.readID, s.readOff = id, off
+	}
+	return nil
+}
+
+// persist the read position atomically
+func (s *Spool) saveCursor() error {
+	path := filepath.Join(s.cfg.Dir, cursorFile)
+	tmp := path + ".tmp"
+	data := fmt.Sprintf("%d %d\n", s.readID, s.readOff)
+	if err := os.WriteFile(tmp, []byte(data), 0o640); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// file path of a segment
+func (s *Spool) segmentPath(id uint64) string {
+	return filepath.Join(s.cfg.Dir, fmt.Sprintf("%020d%s", id, segmentExt))
+}
@@ -0,0 +1,209 @@
+package queue_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"data_agent/internal/queue"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// collect drains the spool into a slice of strings
+func collect(t *testing.T, s *queue.Spool) []string {
+	var got []string
+	_, err := s.Drain(func(body []byte) error {
+		got = append(got, string(body))
+		return nil
+	})
+	require.NoError(t, err)
+	return got
+}
+
+// TestSpoolDrainKeepsOrder verifies messages are drained in the order they were appended
+func TestSpoolDrainKeepsOrder(t *testing.T) {
+	s, err := queue.OpenSpool(queue.SpoolConfig{Dir: t.TempDir(), MaxSegmentBytes: 64})
+	require.NoError(t, err)
+	defer s.Close()
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, s.Append([]byte(fmt.Sprintf("msg-%d", i))))
+	}
+	assert.True(t, s.Pending())
+
+	got := collect(t, s)
+	assert.Len(t, got, 10)
+	assert.Equal(t, "msg-0", got[0])
+	assert.Equal(t, "msg-9", got[9])
+	assert.False(t, s.Pending())
+	assert.Zero(t, s.Size())
+}
+
+// TestSpoolResumesAfterFailedSend ensures a failed send keeps the message for the next drain
+func TestSpoolResumesAfterFailedSend(t *testing.T) {
+	s, err := queue.OpenSpool(queue.SpoolConfig{Dir: t.TempDir()})
+	require.NoError(t, err)
+	defer s.Close()
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, s.Append([]byte(fmt.Sprintf("msg-%d", i))))
+	}
+
+	calls := 0
+	sent, err := s.Drain(func(body []byte) error {
+		calls++
+		if calls == 2 {
+			return errors.New("broker gone")
+		}
+		return nil
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, sent)
+
+	assert.Equal(t, []string{"msg-1", "msg-2"}, collect(t, s))
+}
+
+// TestSpoolSurvivesReopen checks that spooled messages and the read position persist across restarts
+func TestSpoolSurvivesReopen(t *testing.T) {
+	dir := t.TempDir()
+	s, err := queue.OpenSpool(queue.SpoolConfig{Dir: dir})
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, s.Append([]byte(fmt.Sprintf("msg-%d", i))))
+	}
+	_, err = s.Drain(func(body []byte) error {
+		if string(body) == "msg-1" {
+			return errors.New("broker gone")
+		}
+		return nil
+	})
+	assert.Error(t, err)
+	require.NoError(t, s.Close())
+
+	s, err = queue.OpenSpool(queue.SpoolConfig{Dir: dir})
+	require.NoError(t, err)
+	defer s.Close()
+
+	require.NoError(t, s.Append([]byte("msg-3")))
+	assert.Equal(t, []string{"msg-1", "msg-2", "msg-3"}, collect(t, s))
+}
+
+// TestSpoolSkipsTornRecord ensures a partially written record does not block the spool
+func TestSpoolSkipsTornRecord(t *testing.T) {
+	dir := t.TempDir()
+	s, err := queue.OpenSpool(queue.SpoolConfig{Dir: dir})
+	require.NoError(t, err)
+	require.NoError(t, s.Append([]byte("complete")))
+	require.NoError(t, s.Close())
+
+	// simulate a crash in the middle of writing the next record
+	segments, err := filepath.Glob(filepath.Join(dir, "*.seg"))
+	require.NoError(t, err)
+	require.Len(t, segments, 1)
+	f, err := os.OpenFile(segments[0], os.O_WRONLY|os.O_APPEND, 0o640)
+	require.NoError(t, err)
+	_, err = f.Write([]byte{0, 0, 0, 42, 1, 2})
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	s, err = queue.OpenSpool(queue.SpoolConfig{Dir: dir})
+	require.NoError(t, err)
+	defer s.Close()
+
+	assert.Equal(t, []string{"complete"}, collect(t, s))
+}
+
+// TestSpoolDropsOldestOverSizeLimit verifies the size cap drops the oldest segments first
+func TestSpoolDropsOldestOverSizeLimit(t *testing.T) {
+	s, err := queue.OpenSpool(queue.SpoolConfig{Dir: t.TempDir(), MaxSegmentBytes: 32, MaxTotalBytes: 64})
+	require.NoError(t, err)
+	defer s.Close()
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, s.Append([]byte(fmt.Sprintf("msg-%d", i))))
+	}
+	assert.LessOrEqual(t, s.Size(), int64(64))
+
+	got := collect(t, s)
+	assert.NotEmpty(t, got)
+	assert.Less(t, len(got), 10)
+	assert.Equal(t, "msg-9", got[len(got)-1])
+}
+
+// TestSpoolSetLimits verifies that a lowered size limit applies to an open spool
+func TestSpoolSetLimits(t *testing.T) {
+	s, err := queue.OpenSpool(queue.SpoolConfig{Dir: t.TempDir(), MaxSegmentBytes: 32})
+	require.NoError(t, err)
+	defer s.Close()
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, s.Append([]byte(fmt.Sprintf("msg-%d", i))))
+	}
+	require.Greater(t, s.Size(), int64(64))
+
+	s.SetLimits(64, time.Hour)
+	assert.LessOrEqual(t, s.Size(), int64(64))
+	got := collect(t, s)
+	assert.Equal(t, "msg-9", got[len(got)-1])
+}
+
+// TestSpoolDropsExpiredSegments verifies segments older than the age limit are discarded
+func TestSpoolDropsExpiredSegments(t *testing.T) {
+	dir := t.TempDir()
+	s, err := queue.OpenSpool(queue.SpoolConfig{Dir: dir, MaxAge: time.Hour})
+	require.NoError(t, err)
+	require.NoError(t, s.Append([]byte("stale")))
+	require.NoError(t, s.Close())
+
+	segments, err := filepath.Glob(filepath.Join(dir, "*.seg"))
+	require.NoError(t, err)
+	old := time.Now().Add(-2 * time.Hour)
+	for _, seg := range segments {
+		require.NoError(t, os.Chtimes(seg, old, old))
+	}
+
+	s, err = queue.OpenSpool(queue.SpoolConfig{Dir: dir, MaxAge: time.Hour})
+	require.NoError(t, err)
+	defer s.Close()
+
+	assert.False(t, s.Pending())
+	assert.Empty(t, collect(t, s))
+}
+
+// TestSpoolPrepend verifies that prepended messages are drained before the unread spooled ones
+func TestSpoolPrepend(t *testing.T) {
+	dir := t.TempDir()
+	s, err := queue.OpenSpool(queue.SpoolConfig{Dir: dir})
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, s.Append([]byte(fmt.Sprintf("msg-%d", i))))
+	}
+	// msg-0 is sent, the drain stops at msg-1
+	_, err = s.Drain(func(body []byte) error {
+		if string(body) == "msg-1" {
+			return errors.New("broker gone")
+		}
+		return nil
+	})
+	require.Error(t, err)
+
+	require.NoError(t, s.Prepend([][]byte{[]byte("old-0"), []byte("old-1")}))
+	require.NoError(t, s.Append([]byte("msg-3")))
+	require.NoError(t, s.Close())
+
+	// the order survives a restart
+	s, err = queue.OpenSpool(queue.SpoolConfig{Dir: dir})
+	require.NoError(t, err)
+	defer s.Close()
+	assert.Equal(t, []string{"old-0", "old-1", "msg-1", "msg-2", "msg-3"}, collect(t, s))
+
+	require.NoError(t, s.Prepend([][]byte{[]byte("only")}))
+	assert.Equal(t, []string{"only"}, collect(t, s))
+}
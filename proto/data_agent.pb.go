@@ -58,32 +58,1480 @@ func (*Empty) Descriptor() ([]byte, []int) {
 }
 
 type Host struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Id                 int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Hostname           string                 `protobuf:"bytes,2,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Os                 string                 `protobuf:"bytes,3,opt,name=os,proto3" json:"os,omitempty"`
+	Platform           string                 `protobuf:"bytes,4,opt,name=platform,proto3" json:"platform,omitempty"`
+	PlatformVer        string                 `protobuf:"bytes,5,opt,name=platform_ver,json=platformVer,proto3" json:"platform_ver,omitempty"`
+	KernelVer          string                 `protobuf:"bytes,6,opt,name=kernel_ver,json=kernelVer,proto3" json:"kernel_ver,omitempty"`
+	MachineId          string                 `protobuf:"bytes,7,opt,name=machine_id,json=machineId,proto3" json:"machine_id,omitempty"`
+	CpuModel           string                 `protobuf:"bytes,8,opt,name=cpu_model,json=cpuModel,proto3" json:"cpu_model,omitempty"`
+	CpuCores           int32                  `protobuf:"varint,9,opt,name=cpu_cores,json=cpuCores,proto3" json:"cpu_cores,omitempty"`
+	CpuThreads         int32                  `protobuf:"varint,10,opt,name=cpu_threads,json=cpuThreads,proto3" json:"cpu_threads,omitempty"`
+	MemoryTotal        uint64                 `protobuf:"varint,11,opt,name=memory_total,json=memoryTotal,proto3" json:"memory_total,omitempty"`
+	Interfaces         []*NetInterface        `protobuf:"bytes,12,rep,name=interfaces,proto3" json:"interfaces,omitempty"`
+	BootTime           string                 `protobuf:"bytes,13,opt,name=boot_time,json=bootTime,proto3" json:"boot_time,omitempty"`
+	Virtualization     string                 `protobuf:"bytes,14,opt,name=virtualization,proto3" json:"virtualization,omitempty"`
+	VirtualizationRole string                 `protobuf:"bytes,15,opt,name=virtualization_role,json=virtualizationRole,proto3" json:"virtualization_role,omitempty"`
+	Arch               string                 `protobuf:"bytes,16,opt,name=arch,proto3" json:"arch,omitempty"`
+	Labels             map[string]string      `protobuf:"bytes,17,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *Host) Reset() {
+	*x = Host{}
+	mi := &file_proto_data_agent_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Host) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Host) ProtoMessage() {}
+
+func (x *Host) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_data_agent_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Host.ProtoReflect.Descriptor instead.
+func (*Host) Descriptor() ([]byte, []int) {
+	return file_proto_data_agent_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Host) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Host) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *Host) GetOs() string {
+	if x != nil {
+		return x.Os
+	}
+	return ""
+}
+
+func (x *Host) GetPlatform() string {
+	if x != nil {
+		return x.Platform
+	}
+	return ""
+}
+
+func (x *Host) GetPlatformVer() string {
+	if x != nil {
+		return x.PlatformVer
+	}
+	return ""
+}
+
+func (x *Host) GetKernelVer() string {
+	if x != nil {
+		return x.KernelVer
+	}
+	return ""
+}
+
+func (x *Host) GetMachineId() string {
+	if x != nil {
+		return x.MachineId
+	}
+	return ""
+}
+
+func (x *Host) GetCpuModel() string {
+	if x != nil {
+		return x.CpuModel
+	}
+	return ""
+}
+
+func (x *Host) GetCpuCores() int32 {
+	if x != nil {
+		return x.CpuCores
+	}
+	return 0
+}
+
+func (x *Host) GetCpuThreads() int32 {
+	if x != nil {
+		return x.CpuThreads
+	}
+	return 0
+}
+
+func (x *Host) GetMemoryTotal() uint64 {
+	if x != nil {
+		return x.MemoryTotal
+	}
+	return 0
+}
+
+func (x *Host) GetInterfaces() []*NetInterface {
+	if x != nil {
+		return x.Interfaces
+	}
+	return nil
+}
+
+func (x *Host) GetBootTime() string {
+	if x != nil {
+		return x.BootTime
+	}
+	return ""
+}
+
+func (x *Host) GetVirtualization() string {
+	if x != nil {
+		return x.Virtualization
+	}
+	return ""
+}
+
+func (x *Host) GetVirtualizationRole() string {
+	if x != nil {
+		return x.VirtualizationRole
+	}
+	return ""
+}
+
+func (x *Host) GetArch() string {
+	if x != nil {
+		return x.Arch
+	}
+	return ""
+}
+
+func (x *Host) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+type NetInterface struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Mac           string                 `protobuf:"bytes,2,opt,name=mac,proto3" json:"mac,omitempty"`
+	Addrs         []string               `protobuf:"bytes,3,rep,name=addrs,proto3" json:"addrs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetInterface) Reset() {
+	*x = NetInterface{}
+	mi := &file_proto_data_agent_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetInterface) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetInterface) ProtoMessage() {}
+
+func (x *NetInterface) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_data_agent_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetInterface.ProtoReflect.Descriptor instead.
+func (*NetInterface) Descriptor() ([]byte, []int) {
+	return file_proto_data_agent_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *NetInterface) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *NetInterface) GetMac() string {
+	if x != nil {
+		return x.Mac
+	}
+	return ""
+}
+
+func (x *NetInterface) GetAddrs() []string {
+	if x != nil {
+		return x.Addrs
+	}
+	return nil
+}
+
+type HostFilter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Selector      string                 `protobuf:"bytes,1,opt,name=selector,proto3" json:"selector,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HostFilter) Reset() {
+	*x = HostFilter{}
+	mi := &file_proto_data_agent_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HostFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HostFilter) ProtoMessage() {}
+
+func (x *HostFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_data_agent_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HostFilter.ProtoReflect.Descriptor instead.
+func (*HostFilter) Descriptor() ([]byte, []int) {
+	return file_proto_data_agent_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *HostFilter) GetSelector() string {
+	if x != nil {
+		return x.Selector
+	}
+	return ""
+}
+
+// selects the host like HostName, a hostname shared by several hosts is rejected
+type SetHostLabelsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hostname      string                 `protobuf:"bytes,1,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Labels        map[string]string      `protobuf:"bytes,2,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	HostId        int64                  `protobuf:"varint,3,opt,name=host_id,json=hostId,proto3" json:"host_id,omitempty"`
+	MachineId     string                 `protobuf:"bytes,4,opt,name=machine_id,json=machineId,proto3" json:"machine_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetHostLabelsRequest) Reset() {
+	*x = SetHostLabelsRequest{}
+	mi := &file_proto_data_agent_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetHostLabelsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetHostLabelsRequest) ProtoMessage() {}
+
+func (x *SetHostLabelsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_data_agent_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetHostLabelsRequest.ProtoReflect.Descriptor instead.
+func (*SetHostLabelsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_data_agent_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SetHostLabelsRequest) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *SetHostLabelsRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *SetHostLabelsRequest) GetHostId() int64 {
+	if x != nil {
+		return x.HostId
+	}
+	return 0
+}
+
+func (x *SetHostLabelsRequest) GetMachineId() string {
+	if x != nil {
+		return x.MachineId
+	}
+	return ""
+}
+
+type HostList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hosts         []*Host                `protobuf:"bytes,1,rep,name=hosts,proto3" json:"hosts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HostList) Reset() {
+	*x = HostList{}
+	mi := &file_proto_data_agent_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HostList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HostList) ProtoMessage() {}
+
+func (x *HostList) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_data_agent_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HostList.ProtoReflect.Descriptor instead.
+func (*HostList) Descriptor() ([]byte, []int) {
+	return file_proto_data_agent_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *HostList) GetHosts() []*Host {
+	if x != nil {
+		return x.Hosts
+	}
+	return nil
+}
+
+type HostChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Field         string                 `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	OldValue      string                 `protobuf:"bytes,2,opt,name=old_value,json=oldValue,proto3" json:"old_value,omitempty"`
+	NewValue      string                 `protobuf:"bytes,3,opt,name=new_value,json=newValue,proto3" json:"new_value,omitempty"`
+	Time          string                 `protobuf:"bytes,4,opt,name=time,proto3" json:"time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HostChange) Reset() {
+	*x = HostChange{}
+	mi := &file_proto_data_agent_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HostChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HostChange) ProtoMessage() {}
+
+func (x *HostChange) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_data_agent_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HostChange.ProtoReflect.Descriptor instead.
+func (*HostChange) Descriptor() ([]byte, []int) {
+	return file_proto_data_agent_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *HostChange) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *HostChange) GetOldValue() string {
+	if x != nil {
+		return x.OldValue
+	}
+	return ""
+}
+
+func (x *HostChange) GetNewValue() string {
+	if x != nil {
+		return x.NewValue
+	}
+	return ""
+}
+
+func (x *HostChange) GetTime() string {
+	if x != nil {
+		return x.Time
+	}
+	return ""
+}
+
+type HostHistory struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Changes       []*HostChange          `protobuf:"bytes,1,rep,name=changes,proto3" json:"changes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HostHistory) Reset() {
+	*x = HostHistory{}
+	mi := &file_proto_data_agent_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HostHistory) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HostHistory) ProtoMessage() {}
+
+func (x *HostHistory) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_data_agent_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HostHistory.ProtoReflect.Descriptor instead.
+func (*HostHistory) Descriptor() ([]byte, []int) {
+	return file_proto_data_agent_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *HostHistory) GetChanges() []*HostChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+type Metric struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	HostId int64                  `protobuf:"varint,2,opt,name=host_id,json=hostId,proto3" json:"host_id,omitempty"`
+	Uptime float64                `protobuf:"fixed64,3,opt,name=uptime,proto3" json:"uptime,omitempty"`
+	// unset when the sample has no CPU usage, like the load fields
+	Cpu           *float64  `protobuf:"fixed64,4,opt,name=cpu,proto3,oneof" json:"cpu,omitempty"`
+	Ram           float64   `protobuf:"fixed64,5,opt,name=ram,proto3" json:"ram,omitempty"`
+	Disk          string    `protobuf:"bytes,6,opt,name=disk,proto3" json:"disk,omitempty"`
+	Network       string    `protobuf:"bytes,7,opt,name=network,proto3" json:"network,omitempty"`
+	Time          string    `protobuf:"bytes,8,opt,name=time,proto3" json:"time,omitempty"`
+	CpuCores      string    `protobuf:"bytes,9,opt,name=cpu_cores,json=cpuCores,proto3" json:"cpu_cores,omitempty"`
+	CpuTimes      string    `protobuf:"bytes,10,opt,name=cpu_times,json=cpuTimes,proto3" json:"cpu_times,omitempty"`
+	Load1         *float64  `protobuf:"fixed64,11,opt,name=load1,proto3,oneof" json:"load1,omitempty"`
+	Load5         *float64  `protobuf:"fixed64,12,opt,name=load5,proto3,oneof" json:"load5,omitempty"`
+	Load15        *float64  `protobuf:"fixed64,13,opt,name=load15,proto3,oneof" json:"load15,omitempty"`
+	DiskIo        []*DiskIO `protobuf:"bytes,14,rep,name=disk_io,json=diskIo,proto3" json:"disk_io,omitempty"`
+	Memory        string    `protobuf:"bytes,15,opt,name=memory,proto3" json:"memory,omitempty"`
+	Pressure      string    `protobuf:"bytes,16,opt,name=pressure,proto3" json:"pressure,omitempty"`
+	Sensors       string    `protobuf:"bytes,17,opt,name=sensors,proto3" json:"sensors,omitempty"`
+	Sockets       string    `protobuf:"bytes,18,opt,name=sockets,proto3" json:"sockets,omitempty"`
+	Plugins       string    `protobuf:"bytes,19,opt,name=plugins,proto3" json:"plugins,omitempty"`
+	Logs          string    `protobuf:"bytes,20,opt,name=logs,proto3" json:"logs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Metric) Reset() {
+	*x = Metric{}
+	mi := &file_proto_data_agent_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Metric) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Metric) ProtoMessage() {}
+
+func (x *Metric) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_data_agent_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Metric.ProtoReflect.Descriptor instead.
+func (*Metric) Descriptor() ([]byte, []int) {
+	return file_proto_data_agent_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Metric) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Metric) GetHostId() int64 {
+	if x != nil {
+		return x.HostId
+	}
+	return 0
+}
+
+func (x *Metric) GetUptime() float64 {
+	if x != nil {
+		return x.Uptime
+	}
+	return 0
+}
+
+func (x *Metric) GetCpu() float64 {
+	if x != nil && x.Cpu != nil {
+		return *x.Cpu
+	}
+	return 0
+}
+
+func (x *Metric) GetRam() float64 {
+	if x != nil {
+		return x.Ram
+	}
+	return 0
+}
+
+func (x *Metric) GetDisk() string {
+	if x != nil {
+		return x.Disk
+	}
+	return ""
+}
+
+func (x *Metric) GetNetwork() string {
+	if x != nil {
+		return x.Network
+	}
+	return ""
+}
+
+func (x *Metric) GetTime() string {
+	if x != nil {
+		return x.Time
+	}
+	return ""
+}
+
+func (x *Metric) GetCpuCores() string {
+	if x != nil {
+		return x.CpuCores
+	}
+	return ""
+}
+
+func (x *Metric) GetCpuTimes() string {
+	if x != nil {
+		return x.CpuTimes
+	}
+	return ""
+}
+
+func (x *Metric) GetLoad1() float64 {
+	if x != nil && x.Load1 != nil {
+		return *x.Load1
+	}
+	return 0
+}
+
+func (x *Metric) GetLoad5() float64 {
+	if x != nil && x.Load5 != nil {
+		return *x.Load5
+	}
+	return 0
+}
+
+func (x *Metric) GetLoad15() float64 {
+	if x != nil && x.Load15 != nil {
+		return *x.Load15
+	}
+	return 0
+}
+
+func (x *Metric) GetDiskIo() []*DiskIO {
+	if x != nil {
+		return x.DiskIo
+	}
+	return nil
+}
+
+func (x *Metric) GetMemory() string {
+	if x != nil {
+		return x.Memory
+	}
+	return ""
+}
+
+func (x *Metric) GetPressure() string {
+	if x != nil {
+		return x.Pressure
+	}
+	return ""
+}
+
+func (x *Metric) GetSensors() string {
+	if x != nil {
+		return x.Sensors
+	}
+	return ""
+}
+
+func (x *Metric) GetSockets() string {
+	if x != nil {
+		return x.Sockets
+	}
+	return ""
+}
+
+func (x *Metric) GetPlugins() string {
+	if x != nil {
+		return x.Plugins
+	}
+	return ""
+}
+
+func (x *Metric) GetLogs() string {
+	if x != nil {
+		return x.Logs
+	}
+	return ""
+}
+
+type DiskIO struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Device            string                 `protobuf:"bytes,1,opt,name=device,proto3" json:"device,omitempty"`
+	ReadCount         uint64                 `protobuf:"varint,2,opt,name=read_count,json=readCount,proto3" json:"read_count,omitempty"`
+	WriteCount        uint64                 `protobuf:"varint,3,opt,name=write_count,json=writeCount,proto3" json:"write_count,omitempty"`
+	ReadBytes         uint64                 `protobuf:"varint,4,opt,name=read_bytes,json=readBytes,proto3" json:"read_bytes,omitempty"`
+	WriteBytes        uint64                 `protobuf:"varint,5,opt,name=write_bytes,json=writeBytes,proto3" json:"write_bytes,omitempty"`
+	ReadTimeMs        uint64                 `protobuf:"varint,6,opt,name=read_time_ms,json=readTimeMs,proto3" json:"read_time_ms,omitempty"`
+	WriteTimeMs       uint64                 `protobuf:"varint,7,opt,name=write_time_ms,json=writeTimeMs,proto3" json:"write_time_ms,omitempty"`
+	IoTimeMs          uint64                 `protobuf:"varint,8,opt,name=io_time_ms,json=ioTimeMs,proto3" json:"io_time_ms,omitempty"`
+	WeightedIoMs      uint64                 `protobuf:"varint,9,opt,name=weighted_io_ms,json=weightedIoMs,proto3" json:"weighted_io_ms,omitempty"`
+	InProgress        uint64                 `protobuf:"varint,10,opt,name=in_progress,json=inProgress,proto3" json:"in_progress,omitempty"`
+	ReadOpsPerSec     float64                `protobuf:"fixed64,11,opt,name=read_ops_per_sec,json=readOpsPerSec,proto3" json:"read_ops_per_sec,omitempty"`
+	WriteOpsPerSec    float64                `protobuf:"fixed64,12,opt,name=write_ops_per_sec,json=writeOpsPerSec,proto3" json:"write_ops_per_sec,omitempty"`
+	ReadBytesPerSec   float64                `protobuf:"fixed64,13,opt,name=read_bytes_per_sec,json=readBytesPerSec,proto3" json:"read_bytes_per_sec,omitempty"`
+	WriteBytesPerSec  float64                `protobuf:"fixed64,14,opt,name=write_bytes_per_sec,json=writeBytesPerSec,proto3" json:"write_bytes_per_sec,omitempty"`
+	UtilPercent       float64                `protobuf:"fixed64,15,opt,name=util_percent,json=utilPercent,proto3" json:"util_percent,omitempty"`
+	AvgReadLatencyMs  float64                `protobuf:"fixed64,16,opt,name=avg_read_latency_ms,json=avgReadLatencyMs,proto3" json:"avg_read_latency_ms,omitempty"`
+	AvgWriteLatencyMs float64                `protobuf:"fixed64,17,opt,name=avg_write_latency_ms,json=avgWriteLatencyMs,proto3" json:"avg_write_latency_ms,omitempty"`
+	AvgQueueSize      float64                `protobuf:"fixed64,18,opt,name=avg_queue_size,json=avgQueueSize,proto3" json:"avg_queue_size,omitempty"`
+	// disk, partition or stacked, only disks are summed to get the I/O of a host
+	Kind          string `protobuf:"bytes,19,opt,name=kind,proto3" json:"kind,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiskIO) Reset() {
+	*x = DiskIO{}
+	mi := &file_proto_data_agent_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiskIO) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiskIO) ProtoMessage() {}
+
+func (x *DiskIO) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_data_agent_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiskIO.ProtoReflect.Descriptor instead.
+func (*DiskIO) Descriptor() ([]byte, []int) {
+	return file_proto_data_agent_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DiskIO) GetDevice() string {
+	if x != nil {
+		return x.Device
+	}
+	return ""
+}
+
+func (x *DiskIO) GetReadCount() uint64 {
+	if x != nil {
+		return x.ReadCount
+	}
+	return 0
+}
+
+func (x *DiskIO) GetWriteCount() uint64 {
+	if x != nil {
+		return x.WriteCount
+	}
+	return 0
+}
+
+func (x *DiskIO) GetReadBytes() uint64 {
+	if x != nil {
+		return x.ReadBytes
+	}
+	return 0
+}
+
+func (x *DiskIO) GetWriteBytes() uint64 {
+	if x != nil {
+		return x.WriteBytes
+	}
+	return 0
+}
+
+func (x *DiskIO) GetReadTimeMs() uint64 {
+	if x != nil {
+		return x.ReadTimeMs
+	}
+	return 0
+}
+
+func (x *DiskIO) GetWriteTimeMs() uint64 {
+	if x != nil {
+		return x.WriteTimeMs
+	}
+	return 0
+}
+
+func (x *DiskIO) GetIoTimeMs() uint64 {
+	if x != nil {
+		return x.IoTimeMs
+	}
+	return 0
+}
+
+func (x *DiskIO) GetWeightedIoMs() uint64 {
+	if x != nil {
+		return x.WeightedIoMs
+	}
+	return 0
+}
+
+func (x *DiskIO) GetInProgress() uint64 {
+	if x != nil {
+		return x.InProgress
+	}
+	return 0
+}
+
+func (x *DiskIO) GetReadOpsPerSec() float64 {
+	if x != nil {
+		return x.ReadOpsPerSec
+	}
+	return 0
+}
+
+func (x *DiskIO) GetWriteOpsPerSec() float64 {
+	if x != nil {
+		return x.WriteOpsPerSec
+	}
+	return 0
+}
+
+func (x *DiskIO) GetReadBytesPerSec() float64 {
+	if x != nil {
+		return x.ReadBytesPerSec
+	}
+	return 0
+}
+
+func (x *DiskIO) GetWriteBytesPerSec() float64 {
+	if x != nil {
+		return x.WriteBytesPerSec
+	}
+	return 0
+}
+
+func (x *DiskIO) GetUtilPercent() float64 {
+	if x != nil {
+		return x.UtilPercent
+	}
+	return 0
+}
+
+func (x *DiskIO) GetAvgReadLatencyMs() float64 {
+	if x != nil {
+		return x.AvgReadLatencyMs
+	}
+	return 0
+}
+
+func (x *DiskIO) GetAvgWriteLatencyMs() float64 {
+	if x != nil {
+		return x.AvgWriteLatencyMs
+	}
+	return 0
+}
+
+func (x *DiskIO) GetAvgQueueSize() float64 {
+	if x != nil {
+		return x.AvgQueueSize
+	}
+	return 0
+}
+
+func (x *DiskIO) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+type MetricList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Metrics       []*Metric              `protobuf:"bytes,1,rep,name=metrics,proto3" json:"metrics,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MetricList) Reset() {
+	*x = MetricList{}
+	mi := &file_proto_data_agent_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MetricList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetricList) ProtoMessage() {}
+
+func (x *MetricList) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_data_agent_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetricList.ProtoReflect.Descriptor instead.
+func (*MetricList) Descriptor() ([]byte, []int) {
+	return file_proto_data_agent_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *MetricList) GetMetrics() []*Metric {
+	if x != nil {
+		return x.Metrics
+	}
+	return nil
+}
+
+// selects one host by id, machine ID or hostname, a hostname shared by several hosts is rejected
+type HostName struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hostname      string                 `protobuf:"bytes,1,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	HostId        int64                  `protobuf:"varint,2,opt,name=host_id,json=hostId,proto3" json:"host_id,omitempty"`
+	MachineId     string                 `protobuf:"bytes,3,opt,name=machine_id,json=machineId,proto3" json:"machine_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HostName) Reset() {
+	*x = HostName{}
+	mi := &file_proto_data_agent_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HostName) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HostName) ProtoMessage() {}
+
+func (x *HostName) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_data_agent_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HostName.ProtoReflect.Descriptor instead.
+func (*HostName) Descriptor() ([]byte, []int) {
+	return file_proto_data_agent_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *HostName) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *HostName) GetHostId() int64 {
+	if x != nil {
+		return x.HostId
+	}
+	return 0
+}
+
+func (x *HostName) GetMachineId() string {
+	if x != nil {
+		return x.MachineId
+	}
+	return ""
+}
+
+type Process struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pid           int32                  `protobuf:"varint,1,opt,name=pid,proto3" json:"pid,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	User          string                 `protobuf:"bytes,3,opt,name=user,proto3" json:"user,omitempty"`
+	Cmdline       string                 `protobuf:"bytes,4,opt,name=cmdline,proto3" json:"cmdline,omitempty"`
+	CpuPercent    float64                `protobuf:"fixed64,5,opt,name=cpu_percent,json=cpuPercent,proto3" json:"cpu_percent,omitempty"`
+	Rss           uint64                 `protobuf:"varint,6,opt,name=rss,proto3" json:"rss,omitempty"`
+	Threads       int32                  `protobuf:"varint,7,opt,name=threads,proto3" json:"threads,omitempty"`
+	OpenFds       int32                  `protobuf:"varint,8,opt,name=open_fds,json=openFds,proto3" json:"open_fds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Process) Reset() {
+	*x = Process{}
+	mi := &file_proto_data_agent_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Process) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Process) ProtoMessage() {}
+
+func (x *Process) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_data_agent_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Process.ProtoReflect.Descriptor instead.
+func (*Process) Descriptor() ([]byte, []int) {
+	return file_proto_data_agent_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Process) GetPid() int32 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+func (x *Process) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Process) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *Process) GetCmdline() string {
+	if x != nil {
+		return x.Cmdline
+	}
+	return ""
+}
+
+func (x *Process) GetCpuPercent() float64 {
+	if x != nil {
+		return x.CpuPercent
+	}
+	return 0
+}
+
+func (x *Process) GetRss() uint64 {
+	if x != nil {
+		return x.Rss
+	}
+	return 0
+}
+
+func (x *Process) GetThreads() int32 {
+	if x != nil {
+		return x.Threads
+	}
+	return 0
+}
+
+func (x *Process) GetOpenFds() int32 {
+	if x != nil {
+		return x.OpenFds
+	}
+	return 0
+}
+
+type ProcessList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ByCpu         []*Process             `protobuf:"bytes,1,rep,name=by_cpu,json=byCpu,proto3" json:"by_cpu,omitempty"`
+	ByRss         []*Process             `protobuf:"bytes,2,rep,name=by_rss,json=byRss,proto3" json:"by_rss,omitempty"`
+	Time          string                 `protobuf:"bytes,3,opt,name=time,proto3" json:"time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProcessList) Reset() {
+	*x = ProcessList{}
+	mi := &file_proto_data_agent_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProcessList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcessList) ProtoMessage() {}
+
+func (x *ProcessList) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_data_agent_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcessList.ProtoReflect.Descriptor instead.
+func (*ProcessList) Descriptor() ([]byte, []int) {
+	return file_proto_data_agent_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ProcessList) GetByCpu() []*Process {
+	if x != nil {
+		return x.ByCpu
+	}
+	return nil
+}
+
+func (x *ProcessList) GetByRss() []*Process {
+	if x != nil {
+		return x.ByRss
+	}
+	return nil
+}
+
+func (x *ProcessList) GetTime() string {
+	if x != nil {
+		return x.Time
+	}
+	return ""
+}
+
+type Cgroup struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	ContainerId   string                 `protobuf:"bytes,2,opt,name=container_id,json=containerId,proto3" json:"container_id,omitempty"`
+	CpuUsageUsec  uint64                 `protobuf:"varint,3,opt,name=cpu_usage_usec,json=cpuUsageUsec,proto3" json:"cpu_usage_usec,omitempty"`
+	CpuUserUsec   uint64                 `protobuf:"varint,4,opt,name=cpu_user_usec,json=cpuUserUsec,proto3" json:"cpu_user_usec,omitempty"`
+	CpuSystemUsec uint64                 `protobuf:"varint,5,opt,name=cpu_system_usec,json=cpuSystemUsec,proto3" json:"cpu_system_usec,omitempty"`
+	CpuPercent    float64                `protobuf:"fixed64,6,opt,name=cpu_percent,json=cpuPercent,proto3" json:"cpu_percent,omitempty"`
+	NrPeriods     uint64                 `protobuf:"varint,7,opt,name=nr_periods,json=nrPeriods,proto3" json:"nr_periods,omitempty"`
+	NrThrottled   uint64                 `protobuf:"varint,8,opt,name=nr_throttled,json=nrThrottled,proto3" json:"nr_throttled,omitempty"`
+	ThrottledUsec uint64                 `protobuf:"varint,9,opt,name=throttled_usec,json=throttledUsec,proto3" json:"throttled_usec,omitempty"`
+	MemoryCurrent uint64                 `protobuf:"varint,10,opt,name=memory_current,json=memoryCurrent,proto3" json:"memory_current,omitempty"`
+	MemoryMax     uint64                 `protobuf:"varint,11,opt,name=memory_max,json=memoryMax,proto3" json:"memory_max,omitempty"`
+	IoReadBytes   uint64                 `protobuf:"varint,12,opt,name=io_read_bytes,json=ioReadBytes,proto3" json:"io_read_bytes,omitempty"`
+	IoWriteBytes  uint64                 `protobuf:"varint,13,opt,name=io_write_bytes,json=ioWriteBytes,proto3" json:"io_write_bytes,omitempty"`
+	IoReadOps     uint64                 `protobuf:"varint,14,opt,name=io_read_ops,json=ioReadOps,proto3" json:"io_read_ops,omitempty"`
+	IoWriteOps    uint64                 `protobuf:"varint,15,opt,name=io_write_ops,json=ioWriteOps,proto3" json:"io_write_ops,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Cgroup) Reset() {
+	*x = Cgroup{}
+	mi := &file_proto_data_agent_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Cgroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Cgroup) ProtoMessage() {}
+
+func (x *Cgroup) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_data_agent_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Cgroup.ProtoReflect.Descriptor instead.
+func (*Cgroup) Descriptor() ([]byte, []int) {
+	return file_proto_data_agent_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Cgroup) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *Cgroup) GetContainerId() string {
+	if x != nil {
+		return x.ContainerId
+	}
+	return ""
+}
+
+func (x *Cgroup) GetCpuUsageUsec() uint64 {
+	if x != nil {
+		return x.CpuUsageUsec
+	}
+	return 0
+}
+
+func (x *Cgroup) GetCpuUserUsec() uint64 {
+	if x != nil {
+		return x.CpuUserUsec
+	}
+	return 0
+}
+
+func (x *Cgroup) GetCpuSystemUsec() uint64 {
+	if x != nil {
+		return x.CpuSystemUsec
+	}
+	return 0
+}
+
+func (x *Cgroup) GetCpuPercent() float64 {
+	if x != nil {
+		return x.CpuPercent
+	}
+	return 0
+}
+
+func (x *Cgroup) GetNrPeriods() uint64 {
+	if x != nil {
+		return x.NrPeriods
+	}
+	return 0
+}
+
+func (x *Cgroup) GetNrThrottled() uint64 {
+	if x != nil {
+		return x.NrThrottled
+	}
+	return 0
+}
+
+func (x *Cgroup) GetThrottledUsec() uint64 {
+	if x != nil {
+		return x.ThrottledUsec
+	}
+	return 0
+}
+
+func (x *Cgroup) GetMemoryCurrent() uint64 {
+	if x != nil {
+		return x.MemoryCurrent
+	}
+	return 0
+}
+
+func (x *Cgroup) GetMemoryMax() uint64 {
+	if x != nil {
+		return x.MemoryMax
+	}
+	return 0
+}
+
+func (x *Cgroup) GetIoReadBytes() uint64 {
+	if x != nil {
+		return x.IoReadBytes
+	}
+	return 0
+}
+
+func (x *Cgroup) GetIoWriteBytes() uint64 {
+	if x != nil {
+		return x.IoWriteBytes
+	}
+	return 0
+}
+
+func (x *Cgroup) GetIoReadOps() uint64 {
+	if x != nil {
+		return x.IoReadOps
+	}
+	return 0
+}
+
+func (x *Cgroup) GetIoWriteOps() uint64 {
+	if x != nil {
+		return x.IoWriteOps
+	}
+	return 0
+}
+
+type CgroupList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cgroups       []*Cgroup              `protobuf:"bytes,1,rep,name=cgroups,proto3" json:"cgroups,omitempty"`
+	Time          string                 `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CgroupList) Reset() {
+	*x = CgroupList{}
+	mi := &file_proto_data_agent_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CgroupList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CgroupList) ProtoMessage() {}
+
+func (x *CgroupList) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_data_agent_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CgroupList.ProtoReflect.Descriptor instead.
+func (*CgroupList) Descriptor() ([]byte, []int) {
+	return file_proto_data_agent_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *CgroupList) GetCgroups() []*Cgroup {
+	if x != nil {
+		return x.Cgroups
+	}
+	return nil
+}
+
+func (x *CgroupList) GetTime() string {
+	if x != nil {
+		return x.Time
+	}
+	return ""
+}
+
+type ProbeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hostname      string                 `protobuf:"bytes,1,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	FailedOnly    bool                   `protobuf:"varint,3,opt,name=failed_only,json=failedOnly,proto3" json:"failed_only,omitempty"`
+	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	HostId        int64                  `protobuf:"varint,5,opt,name=host_id,json=hostId,proto3" json:"host_id,omitempty"`
+	MachineId     string                 `protobuf:"bytes,6,opt,name=machine_id,json=machineId,proto3" json:"machine_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProbeRequest) Reset() {
+	*x = ProbeRequest{}
+	mi := &file_proto_data_agent_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProbeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProbeRequest) ProtoMessage() {}
+
+func (x *ProbeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_data_agent_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProbeRequest.ProtoReflect.Descriptor instead.
+func (*ProbeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_data_agent_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ProbeRequest) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *ProbeRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ProbeRequest) GetFailedOnly() bool {
+	if x != nil {
+		return x.FailedOnly
+	}
+	return false
+}
+
+func (x *ProbeRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ProbeRequest) GetHostId() int64 {
+	if x != nil {
+		return x.HostId
+	}
+	return 0
+}
+
+func (x *ProbeRequest) GetMachineId() string {
+	if x != nil {
+		return x.MachineId
+	}
+	return ""
+}
+
+type ProbeResult struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Hostname      string                 `protobuf:"bytes,2,opt,name=hostname,proto3" json:"hostname,omitempty"`
-	Os            string                 `protobuf:"bytes,3,opt,name=os,proto3" json:"os,omitempty"`
-	Platform      string                 `protobuf:"bytes,4,opt,name=platform,proto3" json:"platform,omitempty"`
-	PlatformVer   string                 `protobuf:"bytes,5,opt,name=platform_ver,json=platformVer,proto3" json:"platform_ver,omitempty"`
-	KernelVer     string                 `protobuf:"bytes,6,opt,name=kernel_ver,json=kernelVer,proto3" json:"kernel_ver,omitempty"`
+	Hostname      string                 `protobuf:"bytes,1,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Target        string                 `protobuf:"bytes,4,opt,name=target,proto3" json:"target,omitempty"`
+	Success       bool                   `protobuf:"varint,5,opt,name=success,proto3" json:"success,omitempty"`
+	LatencyMs     float64                `protobuf:"fixed64,6,opt,name=latency_ms,json=latencyMs,proto3" json:"latency_ms,omitempty"`
+	StatusCode    int32                  `protobuf:"varint,7,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
+	Error         string                 `protobuf:"bytes,8,opt,name=error,proto3" json:"error,omitempty"`
+	Time          string                 `protobuf:"bytes,9,opt,name=time,proto3" json:"time,omitempty"`
+	HostId        int64                  `protobuf:"varint,10,opt,name=host_id,json=hostId,proto3" json:"host_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Host) Reset() {
-	*x = Host{}
-	mi := &file_proto_data_agent_proto_msgTypes[1]
+func (x *ProbeResult) Reset() {
+	*x = ProbeResult{}
+	mi := &file_proto_data_agent_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Host) String() string {
+func (x *ProbeResult) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Host) ProtoMessage() {}
+func (*ProbeResult) ProtoMessage() {}
 
-func (x *Host) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_data_agent_proto_msgTypes[1]
+func (x *ProbeResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_data_agent_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -94,75 +1542,103 @@ func (x *Host) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Host.ProtoReflect.Descriptor instead.
-func (*Host) Descriptor() ([]byte, []int) {
-	return file_proto_data_agent_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use ProbeResult.ProtoReflect.Descriptor instead.
+func (*ProbeResult) Descriptor() ([]byte, []int) {
+	return file_proto_data_agent_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *Host) GetId() int64 {
+func (x *ProbeResult) GetHostname() string {
 	if x != nil {
-		return x.Id
+		return x.Hostname
 	}
-	return 0
+	return ""
 }
 
-func (x *Host) GetHostname() string {
+func (x *ProbeResult) GetName() string {
 	if x != nil {
-		return x.Hostname
+		return x.Name
 	}
 	return ""
 }
 
-func (x *Host) GetOs() string {
+func (x *ProbeResult) GetType() string {
 	if x != nil {
-		return x.Os
+		return x.Type
 	}
 	return ""
 }
 
-func (x *Host) GetPlatform() string {
+func (x *ProbeResult) GetTarget() string {
 	if x != nil {
-		return x.Platform
+		return x.Target
 	}
 	return ""
 }
 
-func (x *Host) GetPlatformVer() string {
+func (x *ProbeResult) GetSuccess() bool {
 	if x != nil {
-		return x.PlatformVer
+		return x.Success
+	}
+	return false
+}
+
+func (x *ProbeResult) GetLatencyMs() float64 {
+	if x != nil {
+		return x.LatencyMs
+	}
+	return 0
+}
+
+func (x *ProbeResult) GetStatusCode() int32 {
+	if x != nil {
+		return x.StatusCode
+	}
+	return 0
+}
+
+func (x *ProbeResult) GetError() string {
+	if x != nil {
+		return x.Error
 	}
 	return ""
 }
 
-func (x *Host) GetKernelVer() string {
+func (x *ProbeResult) GetTime() string {
 	if x != nil {
-		return x.KernelVer
+		return x.Time
 	}
 	return ""
 }
 
-type HostList struct {
+func (x *ProbeResult) GetHostId() int64 {
+	if x != nil {
+		return x.HostId
+	}
+	return 0
+}
+
+type ProbeResultList struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Hosts         []*Host                `protobuf:"bytes,1,rep,name=hosts,proto3" json:"hosts,omitempty"`
+	Results       []*ProbeResult         `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *HostList) Reset() {
-	*x = HostList{}
-	mi := &file_proto_data_agent_proto_msgTypes[2]
+func (x *ProbeResultList) Reset() {
+	*x = ProbeResultList{}
+	mi := &file_proto_data_agent_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *HostList) String() string {
+func (x *ProbeResultList) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HostList) ProtoMessage() {}
+func (*ProbeResultList) ProtoMessage() {}
 
-func (x *HostList) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_data_agent_proto_msgTypes[2]
+func (x *ProbeResultList) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_data_agent_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -173,47 +1649,41 @@ func (x *HostList) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HostList.ProtoReflect.Descriptor instead.
-func (*HostList) Descriptor() ([]byte, []int) {
-	return file_proto_data_agent_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use ProbeResultList.ProtoReflect.Descriptor instead.
+func (*ProbeResultList) Descriptor() ([]byte, []int) {
+	return file_proto_data_agent_proto_rawDescGZIP(), []int{18}
 }
 
-func (x *HostList) GetHosts() []*Host {
+func (x *ProbeResultList) GetResults() []*ProbeResult {
 	if x != nil {
-		return x.Hosts
+		return x.Results
 	}
 	return nil
 }
 
-type Metric struct {
+type UnitFilter struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	HostId        int64                  `protobuf:"varint,2,opt,name=host_id,json=hostId,proto3" json:"host_id,omitempty"`
-	Uptime        float64                `protobuf:"fixed64,3,opt,name=uptime,proto3" json:"uptime,omitempty"`
-	Cpu           float64                `protobuf:"fixed64,4,opt,name=cpu,proto3" json:"cpu,omitempty"`
-	Ram           float64                `protobuf:"fixed64,5,opt,name=ram,proto3" json:"ram,omitempty"`
-	Disk          string                 `protobuf:"bytes,6,opt,name=disk,proto3" json:"disk,omitempty"`
-	Network       string                 `protobuf:"bytes,7,opt,name=network,proto3" json:"network,omitempty"`
-	Time          string                 `protobuf:"bytes,8,opt,name=time,proto3" json:"time,omitempty"`
+	Selector      string                 `protobuf:"bytes,1,opt,name=selector,proto3" json:"selector,omitempty"`
+	Unit          string                 `protobuf:"bytes,2,opt,name=unit,proto3" json:"unit,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Metric) Reset() {
-	*x = Metric{}
-	mi := &file_proto_data_agent_proto_msgTypes[3]
+func (x *UnitFilter) Reset() {
+	*x = UnitFilter{}
+	mi := &file_proto_data_agent_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Metric) String() string {
+func (x *UnitFilter) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Metric) ProtoMessage() {}
+func (*UnitFilter) ProtoMessage() {}
 
-func (x *Metric) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_data_agent_proto_msgTypes[3]
+func (x *UnitFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_data_agent_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -224,133 +1694,155 @@ func (x *Metric) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Metric.ProtoReflect.Descriptor instead.
-func (*Metric) Descriptor() ([]byte, []int) {
-	return file_proto_data_agent_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use UnitFilter.ProtoReflect.Descriptor instead.
+func (*UnitFilter) Descriptor() ([]byte, []int) {
+	return file_proto_data_agent_proto_rawDescGZIP(), []int{19}
 }
 
-func (x *Metric) GetId() int64 {
+func (x *UnitFilter) GetSelector() string {
 	if x != nil {
-		return x.Id
+		return x.Selector
 	}
-	return 0
+	return ""
 }
 
-func (x *Metric) GetHostId() int64 {
+func (x *UnitFilter) GetUnit() string {
 	if x != nil {
-		return x.HostId
+		return x.Unit
 	}
-	return 0
+	return ""
 }
 
-func (x *Metric) GetUptime() float64 {
-	if x != nil {
-		return x.Uptime
-	}
-	return 0
+type SystemdUnit struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hostname      string                 `protobuf:"bytes,1,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Unit          string                 `protobuf:"bytes,2,opt,name=unit,proto3" json:"unit,omitempty"`
+	LoadState     string                 `protobuf:"bytes,3,opt,name=load_state,json=loadState,proto3" json:"load_state,omitempty"`
+	ActiveState   string                 `protobuf:"bytes,4,opt,name=active_state,json=activeState,proto3" json:"active_state,omitempty"`
+	SubState      string                 `protobuf:"bytes,5,opt,name=sub_state,json=subState,proto3" json:"sub_state,omitempty"`
+	Restarts      uint32                 `protobuf:"varint,6,opt,name=restarts,proto3" json:"restarts,omitempty"`
+	StateChange   string                 `protobuf:"bytes,7,opt,name=state_change,json=stateChange,proto3" json:"state_change,omitempty"`
+	Time          string                 `protobuf:"bytes,8,opt,name=time,proto3" json:"time,omitempty"`
+	HostId        int64                  `protobuf:"varint,9,opt,name=host_id,json=hostId,proto3" json:"host_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Metric) GetCpu() float64 {
-	if x != nil {
-		return x.Cpu
-	}
-	return 0
+func (x *SystemdUnit) Reset() {
+	*x = SystemdUnit{}
+	mi := &file_proto_data_agent_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *Metric) GetRam() float64 {
+func (x *SystemdUnit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SystemdUnit) ProtoMessage() {}
+
+func (x *SystemdUnit) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_data_agent_proto_msgTypes[20]
 	if x != nil {
-		return x.Ram
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *Metric) GetDisk() string {
+// Deprecated: Use SystemdUnit.ProtoReflect.Descriptor instead.
+func (*SystemdUnit) Descriptor() ([]byte, []int) {
+	return file_proto_data_agent_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *SystemdUnit) GetHostname() string {
 	if x != nil {
-		return x.Disk
+		return x.Hostname
 	}
 	return ""
 }
 
-func (x *Metric) GetNetwork() string {
+func (x *SystemdUnit) GetUnit() string {
 	if x != nil {
-		return x.Network
+		return x.Unit
 	}
 	return ""
 }
 
-func (x *Metric) GetTime() string {
+func (x *SystemdUnit) GetLoadState() string {
 	if x != nil {
-		return x.Time
+		return x.LoadState
 	}
 	return ""
 }
 
-type MetricList struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Metrics       []*Metric              `protobuf:"bytes,1,rep,name=metrics,proto3" json:"metrics,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *SystemdUnit) GetActiveState() string {
+	if x != nil {
+		return x.ActiveState
+	}
+	return ""
 }
 
-func (x *MetricList) Reset() {
-	*x = MetricList{}
-	mi := &file_proto_data_agent_proto_msgTypes[4]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *SystemdUnit) GetSubState() string {
+	if x != nil {
+		return x.SubState
+	}
+	return ""
 }
 
-func (x *MetricList) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *SystemdUnit) GetRestarts() uint32 {
+	if x != nil {
+		return x.Restarts
+	}
+	return 0
 }
 
-func (*MetricList) ProtoMessage() {}
-
-func (x *MetricList) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_data_agent_proto_msgTypes[4]
+func (x *SystemdUnit) GetStateChange() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.StateChange
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use MetricList.ProtoReflect.Descriptor instead.
-func (*MetricList) Descriptor() ([]byte, []int) {
-	return file_proto_data_agent_proto_rawDescGZIP(), []int{4}
+func (x *SystemdUnit) GetTime() string {
+	if x != nil {
+		return x.Time
+	}
+	return ""
 }
 
-func (x *MetricList) GetMetrics() []*Metric {
+func (x *SystemdUnit) GetHostId() int64 {
 	if x != nil {
-		return x.Metrics
+		return x.HostId
 	}
-	return nil
+	return 0
 }
 
-type HostName struct {
+type SystemdUnitList struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Hostname      string                 `protobuf:"bytes,1,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Units         []*SystemdUnit         `protobuf:"bytes,1,rep,name=units,proto3" json:"units,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *HostName) Reset() {
-	*x = HostName{}
-	mi := &file_proto_data_agent_proto_msgTypes[5]
+func (x *SystemdUnitList) Reset() {
+	*x = SystemdUnitList{}
+	mi := &file_proto_data_agent_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *HostName) String() string {
+func (x *SystemdUnitList) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HostName) ProtoMessage() {}
+func (*SystemdUnitList) ProtoMessage() {}
 
-func (x *HostName) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_data_agent_proto_msgTypes[5]
+func (x *SystemdUnitList) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_data_agent_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -361,29 +1853,32 @@ func (x *HostName) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HostName.ProtoReflect.Descriptor instead.
-func (*HostName) Descriptor() ([]byte, []int) {
-	return file_proto_data_agent_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use SystemdUnitList.ProtoReflect.Descriptor instead.
+func (*SystemdUnitList) Descriptor() ([]byte, []int) {
+	return file_proto_data_agent_proto_rawDescGZIP(), []int{21}
 }
 
-func (x *HostName) GetHostname() string {
+func (x *SystemdUnitList) GetUnits() []*SystemdUnit {
 	if x != nil {
-		return x.Hostname
+		return x.Units
 	}
-	return ""
+	return nil
 }
 
 type MetricRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Hostname      string                 `protobuf:"bytes,1,opt,name=hostname,proto3" json:"hostname,omitempty"`
 	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Selector      string                 `protobuf:"bytes,3,opt,name=selector,proto3" json:"selector,omitempty"`
+	HostId        int64                  `protobuf:"varint,4,opt,name=host_id,json=hostId,proto3" json:"host_id,omitempty"`
+	MachineId     string                 `protobuf:"bytes,5,opt,name=machine_id,json=machineId,proto3" json:"machine_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *MetricRequest) Reset() {
 	*x = MetricRequest{}
-	mi := &file_proto_data_agent_proto_msgTypes[6]
+	mi := &file_proto_data_agent_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -395,7 +1890,7 @@ func (x *MetricRequest) String() string {
 func (*MetricRequest) ProtoMessage() {}
 
 func (x *MetricRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_data_agent_proto_msgTypes[6]
+	mi := &file_proto_data_agent_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -408,7 +1903,7 @@ func (x *MetricRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MetricRequest.ProtoReflect.Descriptor instead.
 func (*MetricRequest) Descriptor() ([]byte, []int) {
-	return file_proto_data_agent_proto_rawDescGZIP(), []int{6}
+	return file_proto_data_agent_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *MetricRequest) GetHostname() string {
@@ -425,13 +1920,34 @@ func (x *MetricRequest) GetLimit() int32 {
 	return 0
 }
 
+func (x *MetricRequest) GetSelector() string {
+	if x != nil {
+		return x.Selector
+	}
+	return ""
+}
+
+func (x *MetricRequest) GetHostId() int64 {
+	if x != nil {
+		return x.HostId
+	}
+	return 0
+}
+
+func (x *MetricRequest) GetMachineId() string {
+	if x != nil {
+		return x.MachineId
+	}
+	return ""
+}
+
 var File_proto_data_agent_proto protoreflect.FileDescriptor
 
 const file_proto_data_agent_proto_rawDesc = "" +
 	"\n" +
 	"\x16proto/data_agent.proto\x12\n" +
 	"data_agent\"\a\n" +
-	"\x05Empty\"\xa0\x01\n" +
+	"\x05Empty\"\xf2\x04\n" +
 	"\x04Host\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1a\n" +
 	"\bhostname\x18\x02 \x01(\tR\bhostname\x12\x0e\n" +
@@ -439,32 +1955,214 @@ const file_proto_data_agent_proto_rawDesc = "" +
 	"\bplatform\x18\x04 \x01(\tR\bplatform\x12!\n" +
 	"\fplatform_ver\x18\x05 \x01(\tR\vplatformVer\x12\x1d\n" +
 	"\n" +
-	"kernel_ver\x18\x06 \x01(\tR\tkernelVer\"2\n" +
+	"kernel_ver\x18\x06 \x01(\tR\tkernelVer\x12\x1d\n" +
+	"\n" +
+	"machine_id\x18\a \x01(\tR\tmachineId\x12\x1b\n" +
+	"\tcpu_model\x18\b \x01(\tR\bcpuModel\x12\x1b\n" +
+	"\tcpu_cores\x18\t \x01(\x05R\bcpuCores\x12\x1f\n" +
+	"\vcpu_threads\x18\n" +
+	" \x01(\x05R\n" +
+	"cpuThreads\x12!\n" +
+	"\fmemory_total\x18\v \x01(\x04R\vmemoryTotal\x128\n" +
+	"\n" +
+	"interfaces\x18\f \x03(\v2\x18.data_agent.NetInterfaceR\n" +
+	"interfaces\x12\x1b\n" +
+	"\tboot_time\x18\r \x01(\tR\bbootTime\x12&\n" +
+	"\x0evirtualization\x18\x0e \x01(\tR\x0evirtualization\x12/\n" +
+	"\x13virtualization_role\x18\x0f \x01(\tR\x12virtualizationRole\x12\x12\n" +
+	"\x04arch\x18\x10 \x01(\tR\x04arch\x124\n" +
+	"\x06labels\x18\x11 \x03(\v2\x1c.data_agent.Host.LabelsEntryR\x06labels\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"J\n" +
+	"\fNetInterface\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x10\n" +
+	"\x03mac\x18\x02 \x01(\tR\x03mac\x12\x14\n" +
+	"\x05addrs\x18\x03 \x03(\tR\x05addrs\"(\n" +
+	"\n" +
+	"HostFilter\x12\x1a\n" +
+	"\bselector\x18\x01 \x01(\tR\bselector\"\xeb\x01\n" +
+	"\x14SetHostLabelsRequest\x12\x1a\n" +
+	"\bhostname\x18\x01 \x01(\tR\bhostname\x12D\n" +
+	"\x06labels\x18\x02 \x03(\v2,.data_agent.SetHostLabelsRequest.LabelsEntryR\x06labels\x12\x17\n" +
+	"\ahost_id\x18\x03 \x01(\x03R\x06hostId\x12\x1d\n" +
+	"\n" +
+	"machine_id\x18\x04 \x01(\tR\tmachineId\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"2\n" +
 	"\bHostList\x12&\n" +
-	"\x05hosts\x18\x01 \x03(\v2\x10.data_agent.HostR\x05hosts\"\xaf\x01\n" +
+	"\x05hosts\x18\x01 \x03(\v2\x10.data_agent.HostR\x05hosts\"p\n" +
+	"\n" +
+	"HostChange\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12\x1b\n" +
+	"\told_value\x18\x02 \x01(\tR\boldValue\x12\x1b\n" +
+	"\tnew_value\x18\x03 \x01(\tR\bnewValue\x12\x12\n" +
+	"\x04time\x18\x04 \x01(\tR\x04time\"?\n" +
+	"\vHostHistory\x120\n" +
+	"\achanges\x18\x01 \x03(\v2\x16.data_agent.HostChangeR\achanges\"\xab\x04\n" +
 	"\x06Metric\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x17\n" +
 	"\ahost_id\x18\x02 \x01(\x03R\x06hostId\x12\x16\n" +
-	"\x06uptime\x18\x03 \x01(\x01R\x06uptime\x12\x10\n" +
-	"\x03cpu\x18\x04 \x01(\x01R\x03cpu\x12\x10\n" +
+	"\x06uptime\x18\x03 \x01(\x01R\x06uptime\x12\x15\n" +
+	"\x03cpu\x18\x04 \x01(\x01H\x00R\x03cpu\x88\x01\x01\x12\x10\n" +
 	"\x03ram\x18\x05 \x01(\x01R\x03ram\x12\x12\n" +
 	"\x04disk\x18\x06 \x01(\tR\x04disk\x12\x18\n" +
 	"\anetwork\x18\a \x01(\tR\anetwork\x12\x12\n" +
-	"\x04time\x18\b \x01(\tR\x04time\":\n" +
+	"\x04time\x18\b \x01(\tR\x04time\x12\x1b\n" +
+	"\tcpu_cores\x18\t \x01(\tR\bcpuCores\x12\x1b\n" +
+	"\tcpu_times\x18\n" +
+	" \x01(\tR\bcpuTimes\x12\x19\n" +
+	"\x05load1\x18\v \x01(\x01H\x01R\x05load1\x88\x01\x01\x12\x19\n" +
+	"\x05load5\x18\f \x01(\x01H\x02R\x05load5\x88\x01\x01\x12\x1b\n" +
+	"\x06load15\x18\r \x01(\x01H\x03R\x06load15\x88\x01\x01\x12+\n" +
+	"\adisk_io\x18\x0e \x03(\v2\x12.data_agent.DiskIOR\x06diskIo\x12\x16\n" +
+	"\x06memory\x18\x0f \x01(\tR\x06memory\x12\x1a\n" +
+	"\bpressure\x18\x10 \x01(\tR\bpressure\x12\x18\n" +
+	"\asensors\x18\x11 \x01(\tR\asensors\x12\x18\n" +
+	"\asockets\x18\x12 \x01(\tR\asockets\x12\x18\n" +
+	"\aplugins\x18\x13 \x01(\tR\aplugins\x12\x12\n" +
+	"\x04logs\x18\x14 \x01(\tR\x04logsB\x06\n" +
+	"\x04_cpuB\b\n" +
+	"\x06_load1B\b\n" +
+	"\x06_load5B\t\n" +
+	"\a_load15\"\xb8\x05\n" +
+	"\x06DiskIO\x12\x16\n" +
+	"\x06device\x18\x01 \x01(\tR\x06device\x12\x1d\n" +
+	"\n" +
+	"read_count\x18\x02 \x01(\x04R\treadCount\x12\x1f\n" +
+	"\vwrite_count\x18\x03 \x01(\x04R\n" +
+	"writeCount\x12\x1d\n" +
+	"\n" +
+	"read_bytes\x18\x04 \x01(\x04R\treadBytes\x12\x1f\n" +
+	"\vwrite_bytes\x18\x05 \x01(\x04R\n" +
+	"writeBytes\x12 \n" +
+	"\fread_time_ms\x18\x06 \x01(\x04R\n" +
+	"readTimeMs\x12\"\n" +
+	"\rwrite_time_ms\x18\a \x01(\x04R\vwriteTimeMs\x12\x1c\n" +
+	"\n" +
+	"io_time_ms\x18\b \x01(\x04R\bioTimeMs\x12$\n" +
+	"\x0eweighted_io_ms\x18\t \x01(\x04R\fweightedIoMs\x12\x1f\n" +
+	"\vin_progress\x18\n" +
+	" \x01(\x04R\n" +
+	"inProgress\x12'\n" +
+	"\x10read_ops_per_sec\x18\v \x01(\x01R\rreadOpsPerSec\x12)\n" +
+	"\x11write_ops_per_sec\x18\f \x01(\x01R\x0ewriteOpsPerSec\x12+\n" +
+	"\x12read_bytes_per_sec\x18\r \x01(\x01R\x0freadBytesPerSec\x12-\n" +
+	"\x13write_bytes_per_sec\x18\x0e \x01(\x01R\x10writeBytesPerSec\x12!\n" +
+	"\futil_percent\x18\x0f \x01(\x01R\vutilPercent\x12-\n" +
+	"\x13avg_read_latency_ms\x18\x10 \x01(\x01R\x10avgReadLatencyMs\x12/\n" +
+	"\x14avg_write_latency_ms\x18\x11 \x01(\x01R\x11avgWriteLatencyMs\x12$\n" +
+	"\x0eavg_queue_size\x18\x12 \x01(\x01R\favgQueueSize\x12\x12\n" +
+	"\x04kind\x18\x13 \x01(\tR\x04kind\":\n" +
 	"\n" +
 	"MetricList\x12,\n" +
-	"\ametrics\x18\x01 \x03(\v2\x12.data_agent.MetricR\ametrics\"&\n" +
+	"\ametrics\x18\x01 \x03(\v2\x12.data_agent.MetricR\ametrics\"^\n" +
 	"\bHostName\x12\x1a\n" +
-	"\bhostname\x18\x01 \x01(\tR\bhostname\"A\n" +
+	"\bhostname\x18\x01 \x01(\tR\bhostname\x12\x17\n" +
+	"\ahost_id\x18\x02 \x01(\x03R\x06hostId\x12\x1d\n" +
+	"\n" +
+	"machine_id\x18\x03 \x01(\tR\tmachineId\"\xc5\x01\n" +
+	"\aProcess\x12\x10\n" +
+	"\x03pid\x18\x01 \x01(\x05R\x03pid\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04user\x18\x03 \x01(\tR\x04user\x12\x18\n" +
+	"\acmdline\x18\x04 \x01(\tR\acmdline\x12\x1f\n" +
+	"\vcpu_percent\x18\x05 \x01(\x01R\n" +
+	"cpuPercent\x12\x10\n" +
+	"\x03rss\x18\x06 \x01(\x04R\x03rss\x12\x18\n" +
+	"\athreads\x18\a \x01(\x05R\athreads\x12\x19\n" +
+	"\bopen_fds\x18\b \x01(\x05R\aopenFds\"y\n" +
+	"\vProcessList\x12*\n" +
+	"\x06by_cpu\x18\x01 \x03(\v2\x13.data_agent.ProcessR\x05byCpu\x12*\n" +
+	"\x06by_rss\x18\x02 \x03(\v2\x13.data_agent.ProcessR\x05byRss\x12\x12\n" +
+	"\x04time\x18\x03 \x01(\tR\x04time\"\x8d\x04\n" +
+	"\x06Cgroup\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12!\n" +
+	"\fcontainer_id\x18\x02 \x01(\tR\vcontainerId\x12$\n" +
+	"\x0ecpu_usage_usec\x18\x03 \x01(\x04R\fcpuUsageUsec\x12\"\n" +
+	"\rcpu_user_usec\x18\x04 \x01(\x04R\vcpuUserUsec\x12&\n" +
+	"\x0fcpu_system_usec\x18\x05 \x01(\x04R\rcpuSystemUsec\x12\x1f\n" +
+	"\vcpu_percent\x18\x06 \x01(\x01R\n" +
+	"cpuPercent\x12\x1d\n" +
+	"\n" +
+	"nr_periods\x18\a \x01(\x04R\tnrPeriods\x12!\n" +
+	"\fnr_throttled\x18\b \x01(\x04R\vnrThrottled\x12%\n" +
+	"\x0ethrottled_usec\x18\t \x01(\x04R\rthrottledUsec\x12%\n" +
+	"\x0ememory_current\x18\n" +
+	" \x01(\x04R\rmemoryCurrent\x12\x1d\n" +
+	"\n" +
+	"memory_max\x18\v \x01(\x04R\tmemoryMax\x12\"\n" +
+	"\rio_read_bytes\x18\f \x01(\x04R\vioReadBytes\x12$\n" +
+	"\x0eio_write_bytes\x18\r \x01(\x04R\fioWriteBytes\x12\x1e\n" +
+	"\vio_read_ops\x18\x0e \x01(\x04R\tioReadOps\x12 \n" +
+	"\fio_write_ops\x18\x0f \x01(\x04R\n" +
+	"ioWriteOps\"N\n" +
+	"\n" +
+	"CgroupList\x12,\n" +
+	"\acgroups\x18\x01 \x03(\v2\x12.data_agent.CgroupR\acgroups\x12\x12\n" +
+	"\x04time\x18\x02 \x01(\tR\x04time\"\xad\x01\n" +
+	"\fProbeRequest\x12\x1a\n" +
+	"\bhostname\x18\x01 \x01(\tR\bhostname\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1f\n" +
+	"\vfailed_only\x18\x03 \x01(\bR\n" +
+	"failedOnly\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\x12\x17\n" +
+	"\ahost_id\x18\x05 \x01(\x03R\x06hostId\x12\x1d\n" +
+	"\n" +
+	"machine_id\x18\x06 \x01(\tR\tmachineId\"\x86\x02\n" +
+	"\vProbeResult\x12\x1a\n" +
+	"\bhostname\x18\x01 \x01(\tR\bhostname\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x16\n" +
+	"\x06target\x18\x04 \x01(\tR\x06target\x12\x18\n" +
+	"\asuccess\x18\x05 \x01(\bR\asuccess\x12\x1d\n" +
+	"\n" +
+	"latency_ms\x18\x06 \x01(\x01R\tlatencyMs\x12\x1f\n" +
+	"\vstatus_code\x18\a \x01(\x05R\n" +
+	"statusCode\x12\x14\n" +
+	"\x05error\x18\b \x01(\tR\x05error\x12\x12\n" +
+	"\x04time\x18\t \x01(\tR\x04time\x12\x17\n" +
+	"\ahost_id\x18\n" +
+	" \x01(\x03R\x06hostId\"D\n" +
+	"\x0fProbeResultList\x121\n" +
+	"\aresults\x18\x01 \x03(\v2\x17.data_agent.ProbeResultR\aresults\"<\n" +
+	"\n" +
+	"UnitFilter\x12\x1a\n" +
+	"\bselector\x18\x01 \x01(\tR\bselector\x12\x12\n" +
+	"\x04unit\x18\x02 \x01(\tR\x04unit\"\x88\x02\n" +
+	"\vSystemdUnit\x12\x1a\n" +
+	"\bhostname\x18\x01 \x01(\tR\bhostname\x12\x12\n" +
+	"\x04unit\x18\x02 \x01(\tR\x04unit\x12\x1d\n" +
+	"\n" +
+	"load_state\x18\x03 \x01(\tR\tloadState\x12!\n" +
+	"\factive_state\x18\x04 \x01(\tR\vactiveState\x12\x1b\n" +
+	"\tsub_state\x18\x05 \x01(\tR\bsubState\x12\x1a\n" +
+	"\brestarts\x18\x06 \x01(\rR\brestarts\x12!\n" +
+	"\fstate_change\x18\a \x01(\tR\vstateChange\x12\x12\n" +
+	"\x04time\x18\b \x01(\tR\x04time\x12\x17\n" +
+	"\ahost_id\x18\t \x01(\x03R\x06hostId\"@\n" +
+	"\x0fSystemdUnitList\x12-\n" +
+	"\x05units\x18\x01 \x03(\v2\x17.data_agent.SystemdUnitR\x05units\"\x95\x01\n" +
 	"\rMetricRequest\x12\x1a\n" +
 	"\bhostname\x18\x01 \x01(\tR\bhostname\x12\x14\n" +
-	"\x05limit\x18\x02 \x01(\x05R\x05limit2v\n" +
-	"\vHostService\x124\n" +
-	"\tListHosts\x12\x11.data_agent.Empty\x1a\x14.data_agent.HostList\x121\n" +
-	"\aGetHost\x12\x14.data_agent.HostName\x1a\x10.data_agent.Host2\x90\x01\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x1a\n" +
+	"\bselector\x18\x03 \x01(\tR\bselector\x12\x17\n" +
+	"\ahost_id\x18\x04 \x01(\x03R\x06hostId\x12\x1d\n" +
+	"\n" +
+	"machine_id\x18\x05 \x01(\tR\tmachineId2\x81\x02\n" +
+	"\vHostService\x129\n" +
+	"\tListHosts\x12\x16.data_agent.HostFilter\x1a\x14.data_agent.HostList\x121\n" +
+	"\aGetHost\x12\x14.data_agent.HostName\x1a\x10.data_agent.Host\x12?\n" +
+	"\x0eGetHostHistory\x12\x14.data_agent.HostName\x1a\x17.data_agent.HostHistory\x12C\n" +
+	"\rSetHostLabels\x12 .data_agent.SetHostLabelsRequest\x1a\x10.data_agent.Host2\xa5\x03\n" +
 	"\rMetricService\x12@\n" +
-	"\vListMetrics\x12\x19.data_agent.MetricRequest\x1a\x16.data_agent.MetricList\x12=\n" +
-	"\x10GetLatestMetrics\x12\x11.data_agent.Empty\x1a\x16.data_agent.MetricListB\x0eZ\f/proto;protob\x06proto3"
+	"\vListMetrics\x12\x19.data_agent.MetricRequest\x1a\x16.data_agent.MetricList\x12B\n" +
+	"\x10GetLatestMetrics\x12\x16.data_agent.HostFilter\x1a\x16.data_agent.MetricList\x12>\n" +
+	"\rListProcesses\x12\x14.data_agent.HostName\x1a\x17.data_agent.ProcessList\x12;\n" +
+	"\vListCgroups\x12\x14.data_agent.HostName\x1a\x16.data_agent.CgroupList\x12I\n" +
+	"\x10ListProbeResults\x12\x18.data_agent.ProbeRequest\x1a\x1b.data_agent.ProbeResultList\x12F\n" +
+	"\x0fListFailedUnits\x12\x16.data_agent.UnitFilter\x1a\x1b.data_agent.SystemdUnitListB\x0eZ\f/proto;protob\x06proto3"
 
 var (
 	file_proto_data_agent_proto_rawDescOnce sync.Once
@@ -478,32 +2176,72 @@ func file_proto_data_agent_proto_rawDescGZIP() []byte {
 	return file_proto_data_agent_proto_rawDescData
 }
 
-var file_proto_data_agent_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_proto_data_agent_proto_msgTypes = make([]protoimpl.MessageInfo, 25)
 var file_proto_data_agent_proto_goTypes = []any{
-	(*Empty)(nil),         // 0: data_agent.Empty
-	(*Host)(nil),          // 1: data_agent.Host
-	(*HostList)(nil),      // 2: data_agent.HostList
-	(*Metric)(nil),        // 3: data_agent.Metric
-	(*MetricList)(nil),    // 4: data_agent.MetricList
-	(*HostName)(nil),      // 5: data_agent.HostName
-	(*MetricRequest)(nil), // 6: data_agent.MetricRequest
+	(*Empty)(nil),                // 0: data_agent.Empty
+	(*Host)(nil),                 // 1: data_agent.Host
+	(*NetInterface)(nil),         // 2: data_agent.NetInterface
+	(*HostFilter)(nil),           // 3: data_agent.HostFilter
+	(*SetHostLabelsRequest)(nil), // 4: data_agent.SetHostLabelsRequest
+	(*HostList)(nil),             // 5: data_agent.HostList
+	(*HostChange)(nil),           // 6: data_agent.HostChange
+	(*HostHistory)(nil),          // 7: data_agent.HostHistory
+	(*Metric)(nil),               // 8: data_agent.Metric
+	(*DiskIO)(nil),               // 9: data_agent.DiskIO
+	(*MetricList)(nil),           // 10: data_agent.MetricList
+	(*HostName)(nil),             // 11: data_agent.HostName
+	(*Process)(nil),              // 12: data_agent.Process
+	(*ProcessList)(nil),          // 13: data_agent.ProcessList
+	(*Cgroup)(nil),               // 14: data_agent.Cgroup
+	(*CgroupList)(nil),           // 15: data_agent.CgroupList
+	(*ProbeRequest)(nil),         // 16: data_agent.ProbeRequest
+	(*ProbeResult)(nil),          // 17: data_agent.ProbeResult
+	(*ProbeResultList)(nil),      // 18: data_agent.ProbeResultList
+	(*UnitFilter)(nil),           // 19: data_agent.UnitFilter
+	(*SystemdUnit)(nil),          // 20: data_agent.SystemdUnit
+	(*SystemdUnitList)(nil),      // 21: data_agent.SystemdUnitList
+	(*MetricRequest)(nil),        // 22: data_agent.MetricRequest
+	nil,                          // 23: data_agent.Host.LabelsEntry
+	nil,                          // 24: data_agent.SetHostLabelsRequest.LabelsEntry
 }
 var file_proto_data_agent_proto_depIdxs = []int32{
-	1, // 0: data_agent.HostList.hosts:type_name -> data_agent.Host
-	3, // 1: data_agent.MetricList.metrics:type_name -> data_agent.Metric
-	0, // 2: data_agent.HostService.ListHosts:input_type -> data_agent.Empty
-	5, // 3: data_agent.HostService.GetHost:input_type -> data_agent.HostName
-	6, // 4: data_agent.MetricService.ListMetrics:input_type -> data_agent.MetricRequest
-	0, // 5: data_agent.MetricService.GetLatestMetrics:input_type -> data_agent.Empty
-	2, // 6: data_agent.HostService.ListHosts:output_type -> data_agent.HostList
-	1, // 7: data_agent.HostService.GetHost:output_type -> data_agent.Host
-	4, // 8: data_agent.MetricService.ListMetrics:output_type -> data_agent.MetricList
-	4, // 9: data_agent.MetricService.GetLatestMetrics:output_type -> data_agent.MetricList
-	6, // [6:10] is the sub-list for method output_type
-	2, // [2:6] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	2,  // 0: data_agent.Host.interfaces:type_name -> data_agent.NetInterface
+	23, // 1: data_agent.Host.labels:type_name -> data_agent.Host.LabelsEntry
+	24, // 2: data_agent.SetHostLabelsRequest.labels:type_name -> data_agent.SetHostLabelsRequest.LabelsEntry
+	1,  // 3: data_agent.HostList.hosts:type_name -> data_agent.Host
+	6,  // 4: data_agent.HostHistory.changes:type_name -> data_agent.HostChange
+	9,  // 5: data_agent.Metric.disk_io:type_name -> data_agent.DiskIO
+	8,  // 6: data_agent.MetricList.metrics:type_name -> data_agent.Metric
+	12, // 7: data_agent.ProcessList.by_cpu:type_name -> data_agent.Process
+	12, // 8: data_agent.ProcessList.by_rss:type_name -> data_agent.Process
+	14, // 9: data_agent.CgroupList.cgroups:type_name -> data_agent.Cgroup
+	17, // 10: data_agent.ProbeResultList.results:type_name -> data_agent.ProbeResult
+	20, // 11: data_agent.SystemdUnitList.units:type_name -> data_agent.SystemdUnit
+	3,  // 12: data_agent.HostService.ListHosts:input_type -> data_agent.HostFilter
+	11, // 13: data_agent.HostService.GetHost:input_type -> data_agent.HostName
+	11, // 14: data_agent.HostService.GetHostHistory:input_type -> data_agent.HostName
+	4,  // 15: data_agent.HostService.SetHostLabels:input_type -> data_agent.SetHostLabelsRequest
+	22, // 16: data_agent.MetricService.ListMetrics:input_type -> data_agent.MetricRequest
+	3,  // 17: data_agent.MetricService.GetLatestMetrics:input_type -> data_agent.HostFilter
+	11, // 18: data_agent.MetricService.ListProcesses:input_type -> data_agent.HostName
+	11, // 19: data_agent.MetricService.ListCgroups:input_type -> data_agent.HostName
+	16, // 20: data_agent.MetricService.ListProbeResults:input_type -> data_agent.ProbeRequest
+	19, // 21: data_agent.MetricService.ListFailedUnits:input_type -> data_agent.UnitFilter
+	5,  // 22: data_agent.HostService.ListHosts:output_type -> data_agent.HostList
+	1,  // 23: data_agent.HostService.GetHost:output_type -> data_agent.Host
+	7,  // 24: data_agent.HostService.GetHostHistory:output_type -> data_agent.HostHistory
+	1,  // 25: data_agent.HostService.SetHostLabels:output_type -> data_agent.Host
+	10, // 26: data_agent.MetricService.ListMetrics:output_type -> data_agent.MetricList
+	10, // 27: data_agent.MetricService.GetLatestMetrics:output_type -> data_agent.MetricList
+	13, // 28: data_agent.MetricService.ListProcesses:output_type -> data_agent.ProcessList
+	15, // 29: data_agent.MetricService.ListCgroups:output_type -> data_agent.CgroupList
+	18, // 30: data_agent.MetricService.ListProbeResults:output_type -> data_agent.ProbeResultList
+	21, // 31: data_agent.MetricService.ListFailedUnits:output_type -> data_agent.SystemdUnitList
+	22, // [22:32] is the sub-list for method output_type
+	12, // [12:22] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
 }
 
 func init() { file_proto_data_agent_proto_init() }
@@ -511,13 +2249,14 @@ func file_proto_data_agent_proto_init() {
 	if File_proto_data_agent_proto != nil {
 		return
 	}
+	file_proto_data_agent_proto_msgTypes[8].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_data_agent_proto_rawDesc), len(file_proto_data_agent_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   7,
+			NumMessages:   25,
 			NumExtensions: 0,
 			NumServices:   2,
 		},
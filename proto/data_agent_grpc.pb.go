@@ -19,16 +19,20 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	HostService_ListHosts_FullMethodName = "/data_agent.HostService/ListHosts"
-	HostService_GetHost_FullMethodName   = "/data_agent.HostService/GetHost"
+	HostService_ListHosts_FullMethodName      = "/data_agent.HostService/ListHosts"
+	HostService_GetHost_FullMethodName        = "/data_agent.HostService/GetHost"
+	HostService_GetHostHistory_FullMethodName = "/data_agent.HostService/GetHostHistory"
+	HostService_SetHostLabels_FullMethodName  = "/data_agent.HostService/SetHostLabels"
 )
 
 // HostServiceClient is the client API for HostService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type HostServiceClient interface {
-	ListHosts(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*HostList, error)
+	ListHosts(ctx context.Context, in *HostFilter, opts ...grpc.CallOption) (*HostList, error)
 	GetHost(ctx context.Context, in *HostName, opts ...grpc.CallOption) (*Host, error)
+	GetHostHistory(ctx context.Context, in *HostName, opts ...grpc.CallOption) (*HostHistory, error)
+	SetHostLabels(ctx context.Context, in *SetHostLabelsRequest, opts ...grpc.CallOption) (*Host, error)
 }
 
 type hostServiceClient struct {
@@ -39,7 +43,7 @@ func NewHostServiceClient(cc grpc.ClientConnInterface) HostServiceClient {
 	return &hostServiceClient{cc}
 }
 
-func (c *hostServiceClient) ListHosts(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*HostList, error) {
+func (c *hostServiceClient) ListHosts(ctx context.Context, in *HostFilter, opts ...grpc.CallOption) (*HostList, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(HostList)
 	err := c.cc.Invoke(ctx, HostService_ListHosts_FullMethodName, in, out, cOpts...)
@@ -59,12 +63,34 @@ func (c *hostServiceClient) GetHost(ctx context.Context, in *HostName, opts ...g
 	return out, nil
 }
 
+func (c *hostServiceClient) GetHostHistory(ctx context.Context, in *HostName, opts ...grpc.CallOption) (*HostHistory, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HostHistory)
+	err := c.cc.Invoke(ctx, HostService_GetHostHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hostServiceClient) SetHostLabels(ctx context.Context, in *SetHostLabelsRequest, opts ...grpc.CallOption) (*Host, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Host)
+	err := c.cc.Invoke(ctx, HostService_SetHostLabels_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // HostServiceServer is the server API for HostService service.
 // All implementations must embed UnimplementedHostServiceServer
 // for forward compatibility.
 type HostServiceServer interface {
-	ListHosts(context.Context, *Empty) (*HostList, error)
+	ListHosts(context.Context, *HostFilter) (*HostList, error)
 	GetHost(context.Context, *HostName) (*Host, error)
+	GetHostHistory(context.Context, *HostName) (*HostHistory, error)
+	SetHostLabels(context.Context, *SetHostLabelsRequest) (*Host, error)
 	mustEmbedUnimplementedHostServiceServer()
 }
 
@@ -75,12 +101,18 @@ type HostServiceServer interface {
 // pointer dereference when methods are called.
 type UnimplementedHostServiceServer struct{}
 
-func (UnimplementedHostServiceServer) ListHosts(context.Context, *Empty) (*HostList, error) {
+func (UnimplementedHostServiceServer) ListHosts(context.Context, *HostFilter) (*HostList, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListHosts not implemented")
 }
 func (UnimplementedHostServiceServer) GetHost(context.Context, *HostName) (*Host, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetHost not implemented")
 }
+func (UnimplementedHostServiceServer) GetHostHistory(context.Context, *HostName) (*HostHistory, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHostHistory not implemented")
+}
+func (UnimplementedHostServiceServer) SetHostLabels(context.Context, *SetHostLabelsRequest) (*Host, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetHostLabels not implemented")
+}
 func (UnimplementedHostServiceServer) mustEmbedUnimplementedHostServiceServer() {}
 func (UnimplementedHostServiceServer) testEmbeddedByValue()                     {}
 
@@ -103,7 +135,7 @@ func RegisterHostServiceServer(s grpc.ServiceRegistrar, srv HostServiceServer) {
 }
 
 func _HostService_ListHosts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Empty)
+	in := new(HostFilter)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -115,7 +147,7 @@ func _HostService_ListHosts_Handler(srv interface{}, ctx context.Context, dec fu
 		FullMethod: HostService_ListHosts_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(HostServiceServer).ListHosts(ctx, req.(*Empty))
+		return srv.(HostServiceServer).ListHosts(ctx, req.(*HostFilter))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -138,6 +170,42 @@ func _HostService_GetHost_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _HostService_GetHostHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HostName)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HostServiceServer).GetHostHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HostService_GetHostHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HostServiceServer).GetHostHistory(ctx, req.(*HostName))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HostService_SetHostLabels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetHostLabelsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HostServiceServer).SetHostLabels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HostService_SetHostLabels_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HostServiceServer).SetHostLabels(ctx, req.(*SetHostLabelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // HostService_ServiceDesc is the grpc.ServiceDesc for HostService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -153,6 +221,14 @@ var HostService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetHost",
 			Handler:    _HostService_GetHost_Handler,
 		},
+		{
+			MethodName: "GetHostHistory",
+			Handler:    _HostService_GetHostHistory_Handler,
+		},
+		{
+			MethodName: "SetHostLabels",
+			Handler:    _HostService_SetHostLabels_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/data_agent.proto",
@@ -161,6 +237,10 @@ var HostService_ServiceDesc = grpc.ServiceDesc{
 const (
 	MetricService_ListMetrics_FullMethodName      = "/data_agent.MetricService/ListMetrics"
 	MetricService_GetLatestMetrics_FullMethodName = "/data_agent.MetricService/GetLatestMetrics"
+	MetricService_ListProcesses_FullMethodName    = "/data_agent.MetricService/ListProcesses"
+	MetricService_ListCgroups_FullMethodName      = "/data_agent.MetricService/ListCgroups"
+	MetricService_ListProbeResults_FullMethodName = "/data_agent.MetricService/ListProbeResults"
+	MetricService_ListFailedUnits_FullMethodName  = "/data_agent.MetricService/ListFailedUnits"
 )
 
 // MetricServiceClient is the client API for MetricService service.
@@ -168,7 +248,11 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type MetricServiceClient interface {
 	ListMetrics(ctx context.Context, in *MetricRequest, opts ...grpc.CallOption) (*MetricList, error)
-	GetLatestMetrics(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*MetricList, error)
+	GetLatestMetrics(ctx context.Context, in *HostFilter, opts ...grpc.CallOption) (*MetricList, error)
+	ListProcesses(ctx context.Context, in *HostName, opts ...grpc.CallOption) (*ProcessList, error)
+	ListCgroups(ctx context.Context, in *HostName, opts ...grpc.CallOption) (*CgroupList, error)
+	ListProbeResults(ctx context.Context, in *ProbeRequest, opts ...grpc.CallOption) (*ProbeResultList, error)
+	ListFailedUnits(ctx context.Context, in *UnitFilter, opts ...grpc.CallOption) (*SystemdUnitList, error)
 }
 
 type metricServiceClient struct {
@@ -189,7 +273,7 @@ func (c *metricServiceClient) ListMetrics(ctx context.Context, in *MetricRequest
 	return out, nil
 }
 
-func (c *metricServiceClient) GetLatestMetrics(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*MetricList, error) {
+func (c *metricServiceClient) GetLatestMetrics(ctx context.Context, in *HostFilter, opts ...grpc.CallOption) (*MetricList, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(MetricList)
 	err := c.cc.Invoke(ctx, MetricService_GetLatestMetrics_FullMethodName, in, out, cOpts...)
@@ -199,12 +283,56 @@ func (c *metricServiceClient) GetLatestMetrics(ctx context.Context, in *Empty, o
 	return out, nil
 }
 
+func (c *metricServiceClient) ListProcesses(ctx context.Context, in *HostName, opts ...grpc.CallOption) (*ProcessList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProcessList)
+	err := c.cc.Invoke(ctx, MetricService_ListProcesses_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metricServiceClient) ListCgroups(ctx context.Context, in *HostName, opts ...grpc.CallOption) (*CgroupList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CgroupList)
+	err := c.cc.Invoke(ctx, MetricService_ListCgroups_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metricServiceClient) ListProbeResults(ctx context.Context, in *ProbeRequest, opts ...grpc.CallOption) (*ProbeResultList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProbeResultList)
+	err := c.cc.Invoke(ctx, MetricService_ListProbeResults_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metricServiceClient) ListFailedUnits(ctx context.Context, in *UnitFilter, opts ...grpc.CallOption) (*SystemdUnitList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SystemdUnitList)
+	err := c.cc.Invoke(ctx, MetricService_ListFailedUnits_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MetricServiceServer is the server API for MetricService service.
 // All implementations must embed UnimplementedMetricServiceServer
 // for forward compatibility.
 type MetricServiceServer interface {
 	ListMetrics(context.Context, *MetricRequest) (*MetricList, error)
-	GetLatestMetrics(context.Context, *Empty) (*MetricList, error)
+	GetLatestMetrics(context.Context, *HostFilter) (*MetricList, error)
+	ListProcesses(context.Context, *HostName) (*ProcessList, error)
+	ListCgroups(context.Context, *HostName) (*CgroupList, error)
+	ListProbeResults(context.Context, *ProbeRequest) (*ProbeResultList, error)
+	ListFailedUnits(context.Context, *UnitFilter) (*SystemdUnitList, error)
 	mustEmbedUnimplementedMetricServiceServer()
 }
 
@@ -218,9 +346,21 @@ type UnimplementedMetricServiceServer struct{}
 func (UnimplementedMetricServiceServer) ListMetrics(context.Context, *MetricRequest) (*MetricList, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListMetrics not implemented")
 }
-func (UnimplementedMetricServiceServer) GetLatestMetrics(context.Context, *Empty) (*MetricList, error) {
+func (UnimplementedMetricServiceServer) GetLatestMetrics(context.Context, *HostFilter) (*MetricList, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetLatestMetrics not implemented")
 }
+func (UnimplementedMetricServiceServer) ListProcesses(context.Context, *HostName) (*ProcessList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProcesses not implemented")
+}
+func (UnimplementedMetricServiceServer) ListCgroups(context.Context, *HostName) (*CgroupList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCgroups not implemented")
+}
+func (UnimplementedMetricServiceServer) ListProbeResults(context.Context, *ProbeRequest) (*ProbeResultList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProbeResults not implemented")
+}
+func (UnimplementedMetricServiceServer) ListFailedUnits(context.Context, *UnitFilter) (*SystemdUnitList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFailedUnits not implemented")
+}
 func (UnimplementedMetricServiceServer) mustEmbedUnimplementedMetricServiceServer() {}
 func (UnimplementedMetricServiceServer) testEmbeddedByValue()                       {}
 
@@ -261,7 +401,7 @@ func _MetricService_ListMetrics_Handler(srv interface{}, ctx context.Context, de
 }
 
 func _MetricService_GetLatestMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Empty)
+	in := new(HostFilter)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -273,7 +413,79 @@ func _MetricService_GetLatestMetrics_Handler(srv interface{}, ctx context.Contex
 		FullMethod: MetricService_GetLatestMetrics_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MetricServiceServer).GetLatestMetrics(ctx, req.(*Empty))
+		return srv.(MetricServiceServer).GetLatestMetrics(ctx, req.(*HostFilter))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetricService_ListProcesses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HostName)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetricServiceServer).ListProcesses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MetricService_ListProcesses_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetricServiceServer).ListProcesses(ctx, req.(*HostName))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetricService_ListCgroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HostName)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetricServiceServer).ListCgroups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MetricService_ListCgroups_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetricServiceServer).ListCgroups(ctx, req.(*HostName))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetricService_ListProbeResults_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProbeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetricServiceServer).ListProbeResults(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MetricService_ListProbeResults_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetricServiceServer).ListProbeResults(ctx, req.(*ProbeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetricService_ListFailedUnits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnitFilter)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetricServiceServer).ListFailedUnits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MetricService_ListFailedUnits_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetricServiceServer).ListFailedUnits(ctx, req.(*UnitFilter))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -293,6 +505,22 @@ var MetricService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetLatestMetrics",
 			Handler:    _MetricService_GetLatestMetrics_Handler,
 		},
+		{
+			MethodName: "ListProcesses",
+			Handler:    _MetricService_ListProcesses_Handler,
+		},
+		{
+			MethodName: "ListCgroups",
+			Handler:    _MetricService_ListCgroups_Handler,
+		},
+		{
+			MethodName: "ListProbeResults",
+			Handler:    _MetricService_ListProbeResults_Handler,
+		},
+		{
+			MethodName: "ListFailedUnits",
+			Handler:    _MetricService_ListFailedUnits_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/data_agent.proto",